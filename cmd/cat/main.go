@@ -2,7 +2,7 @@
 package main
 
 import (
-	"os" // Provides access to command-line arguments.
+	"os" // Provides access to command-line arguments and process exit.
 
 	// Importing the cat package from the internal project structure.
 	"github.com/drunkleen/unix-tools-go/internal/cat"
@@ -10,9 +10,7 @@ import (
 
 // main is the starting point of the application.
 func main() {
-	// Check if command-line arguments are provided.
-	if len(os.Args) > 1 {
-		// Pass all arguments except the program name to cat.Run.
-		cat.Run(os.Args[1:])
-	}
+	// Pass all arguments except the program name to cat.Run and exit with
+	// the status it reports. Without arguments cat reads standard input.
+	os.Exit(cat.Run(os.Args[1:]))
 }
@@ -0,0 +1,16 @@
+// Package main is the entry point for the Unix tools project.
+package main
+
+import (
+	"os" // Provides access to command-line arguments and process exit.
+
+	// Importing the comm package from the internal project structure.
+	"github.com/drunkleen/unix-tools-go/internal/comm"
+)
+
+// main is the starting point of the application.
+func main() {
+	// Pass command-line arguments (excluding the program name) to comm.Run
+	// and exit with the status it reports.
+	os.Exit(comm.Run(os.Args[1:]))
+}
@@ -2,7 +2,7 @@
 package main
 
 import (
-	"os" // Provides access to command-line arguments and OS functionality.
+	"os" // Provides access to command-line arguments and process exit.
 
 	// Importing the echo package from the internal project structure.
 	"github.com/drunkleen/unix-tools-go/internal/echo"
@@ -10,10 +10,8 @@ import (
 
 // main is the program's entry point.
 func main() {
-	// Check if there are command-line arguments (excluding the program name).
-	// If no arguments are provided, the tool does nothing.
-	if len(os.Args) > 1 {
-		// Pass all arguments except the first (program name) to echo.Run.
-		echo.Run(os.Args[1:])
-	}
+	// Pass command-line arguments (excluding the program name) to echo.Run
+	// and exit with the status it reports. Without arguments echo prints
+	// an empty line.
+	os.Exit(echo.Run(os.Args[1:]))
 }
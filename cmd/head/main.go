@@ -0,0 +1,16 @@
+// Package main is the entry point for the Unix tools project.
+package main
+
+import (
+	"os" // Provides access to command-line arguments and process exit.
+
+	// Importing the head package from the internal project structure.
+	"github.com/drunkleen/unix-tools-go/internal/head"
+)
+
+// main is the starting point of the application.
+func main() {
+	// Pass command-line arguments (excluding the program name) to head.Run
+	// and exit with the status it reports.
+	os.Exit(head.Run(os.Args[1:]))
+}
@@ -2,7 +2,7 @@
 package main
 
 import (
-	"os" // Provides access to command-line arguments.
+	"os" // Provides access to command-line arguments and process exit.
 
 	// Importing the ls package from the internal project structure.
 	"github.com/drunkleen/unix-tools-go/internal/ls"
@@ -10,6 +10,7 @@ import (
 
 // main is the starting point of the application.
 func main() {
-	// Pass command-line arguments (excluding the program name) to ls.Run.
-	ls.Run(os.Args[1:])
+	// Pass command-line arguments (excluding the program name) to ls.Run
+	// and exit with the status it reports.
+	os.Exit(ls.Run(os.Args[1:]))
 }
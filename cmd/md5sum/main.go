@@ -0,0 +1,16 @@
+// Package main is the entry point for the Unix tools project.
+package main
+
+import (
+	"os" // Provides access to command-line arguments and process exit.
+
+	// The checksum tools share the sum package.
+	"github.com/drunkleen/unix-tools-go/internal/sum"
+)
+
+// main is the starting point of the application.
+func main() {
+	// Pass command-line arguments (excluding the program name) to
+	// sum.RunMD5 and exit with the status it reports.
+	os.Exit(sum.RunMD5(os.Args[1:]))
+}
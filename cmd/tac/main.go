@@ -0,0 +1,16 @@
+// Package main is the entry point for the Unix tools project.
+package main
+
+import (
+	"os" // Provides access to command-line arguments and process exit.
+
+	// The tac functionality lives alongside cat in the cat package.
+	"github.com/drunkleen/unix-tools-go/internal/cat"
+)
+
+// main is the starting point of the application.
+func main() {
+	// Pass command-line arguments (excluding the program name) to
+	// cat.RunReverse and exit with the status it reports.
+	os.Exit(cat.RunReverse(os.Args[1:]))
+}
@@ -0,0 +1,16 @@
+// Package main is the entry point for the Unix tools project.
+package main
+
+import (
+	"os" // Provides access to command-line arguments and process exit.
+
+	// The tree functionality lives alongside ls in the ls package.
+	"github.com/drunkleen/unix-tools-go/internal/ls"
+)
+
+// main is the starting point of the application.
+func main() {
+	// Pass command-line arguments (excluding the program name) to
+	// ls.RunTree and exit with the status it reports.
+	os.Exit(ls.RunTree(os.Args[1:]))
+}
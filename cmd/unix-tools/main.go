@@ -0,0 +1,151 @@
+// Package main is the entry point for the unix-tools multi-call binary,
+// which bundles every tool in the project into a single executable.
+//
+// A tool is selected either by the first argument, as in
+// "unix-tools cat file", or, when the binary is installed under a tool's
+// name (for example through a symlink called "cat"), by that name.
+package main
+
+import (
+	"fmt"           // For printing usage information.
+	"io"            // For the writer the usage is printed to.
+	"os"            // Provides access to command-line arguments and process exit.
+	"path/filepath" // For taking the base name of the program path.
+	"slices"        // For listing the tool names in order.
+	"strings"       // For trimming an executable suffix from the program name.
+
+	// Importing the tool packages from the internal project structure.
+	"github.com/drunkleen/unix-tools-go/internal/base64"
+	"github.com/drunkleen/unix-tools-go/internal/basename"
+	"github.com/drunkleen/unix-tools-go/internal/cat"
+	"github.com/drunkleen/unix-tools-go/internal/chmod"
+	"github.com/drunkleen/unix-tools-go/internal/chown"
+	"github.com/drunkleen/unix-tools-go/internal/cli"
+	"github.com/drunkleen/unix-tools-go/internal/cmp"
+	"github.com/drunkleen/unix-tools-go/internal/comm"
+	"github.com/drunkleen/unix-tools-go/internal/cp"
+	"github.com/drunkleen/unix-tools-go/internal/cut"
+	"github.com/drunkleen/unix-tools-go/internal/date"
+	"github.com/drunkleen/unix-tools-go/internal/df"
+	"github.com/drunkleen/unix-tools-go/internal/diff"
+	"github.com/drunkleen/unix-tools-go/internal/dirname"
+	"github.com/drunkleen/unix-tools-go/internal/du"
+	"github.com/drunkleen/unix-tools-go/internal/echo"
+	"github.com/drunkleen/unix-tools-go/internal/expand"
+	"github.com/drunkleen/unix-tools-go/internal/fold"
+	"github.com/drunkleen/unix-tools-go/internal/grep"
+	"github.com/drunkleen/unix-tools-go/internal/head"
+	"github.com/drunkleen/unix-tools-go/internal/id"
+	"github.com/drunkleen/unix-tools-go/internal/ls"
+	"github.com/drunkleen/unix-tools-go/internal/mkdir"
+	"github.com/drunkleen/unix-tools-go/internal/mv"
+	"github.com/drunkleen/unix-tools-go/internal/nl"
+	"github.com/drunkleen/unix-tools-go/internal/paste"
+	"github.com/drunkleen/unix-tools-go/internal/pwd"
+	"github.com/drunkleen/unix-tools-go/internal/rev"
+	"github.com/drunkleen/unix-tools-go/internal/rm"
+	"github.com/drunkleen/unix-tools-go/internal/seq"
+	"github.com/drunkleen/unix-tools-go/internal/sort"
+	"github.com/drunkleen/unix-tools-go/internal/split"
+	"github.com/drunkleen/unix-tools-go/internal/stat"
+	"github.com/drunkleen/unix-tools-go/internal/sum"
+	"github.com/drunkleen/unix-tools-go/internal/tail"
+	"github.com/drunkleen/unix-tools-go/internal/tee"
+	"github.com/drunkleen/unix-tools-go/internal/touch"
+	"github.com/drunkleen/unix-tools-go/internal/tr"
+	"github.com/drunkleen/unix-tools-go/internal/tsort"
+	"github.com/drunkleen/unix-tools-go/internal/unexpand"
+	"github.com/drunkleen/unix-tools-go/internal/uniq"
+	"github.com/drunkleen/unix-tools-go/internal/wc"
+	"github.com/drunkleen/unix-tools-go/internal/yes"
+)
+
+// tools maps each tool name to its entry point.
+var tools = map[string]cli.Tool{
+	"base64":    base64.Run,
+	"basename":  basename.Run,
+	"cat":       cat.Run,
+	"chmod":     chmod.Run,
+	"chown":     chown.Run,
+	"cmp":       cmp.Run,
+	"comm":      comm.Run,
+	"cp":        cp.Run,
+	"cut":       cut.Run,
+	"date":      date.Run,
+	"df":        df.Run,
+	"diff":      diff.Run,
+	"dirname":   dirname.Run,
+	"du":        du.Run,
+	"echo":      echo.Run,
+	"expand":    expand.Run,
+	"fold":      fold.Run,
+	"grep":      grep.Run,
+	"head":      head.Run,
+	"id":        id.Run,
+	"ls":        ls.Run,
+	"md5sum":    sum.RunMD5,
+	"mkdir":     mkdir.Run,
+	"mv":        mv.Run,
+	"nl":        nl.Run,
+	"paste":     paste.Run,
+	"pwd":       pwd.Run,
+	"rev":       rev.Run,
+	"rm":        rm.Run,
+	"seq":       seq.Run,
+	"sha256sum": sum.RunSHA256,
+	"sort":      sort.Run,
+	"split":     split.Run,
+	"stat":      stat.Run,
+	"tac":       cat.RunReverse,
+	"tail":      tail.Run,
+	"tee":       tee.Run,
+	"touch":     touch.Run,
+	"tr":        tr.Run,
+	"tree":      ls.RunTree,
+	"tsort":     tsort.Run,
+	"unexpand":  unexpand.Run,
+	"uniq":      uniq.Run,
+	"wc":        wc.Run,
+	"yes":       yes.Run,
+}
+
+// main is the starting point of the application.
+func main() {
+	os.Exit(dispatch(os.Args, os.Stderr))
+}
+
+// dispatch runs the tool selected by argv, the full command line including
+// the program name, and returns its exit status. If the program itself is
+// named after a tool, that tool receives the remaining arguments;
+// otherwise the first argument names the tool. Unknown tools print the
+// usage to stderr and report cli.ExitUsage.
+func dispatch(argv []string, stderr io.Writer) int {
+	if len(argv) > 0 {
+		name := strings.TrimSuffix(filepath.Base(argv[0]), ".exe")
+		if tool, ok := tools[name]; ok {
+			return tool(argv[1:])
+		}
+	}
+	if len(argv) < 2 {
+		usage(stderr)
+		return cli.ExitUsage
+	}
+	tool, ok := tools[argv[1]]
+	if !ok {
+		cli.Fprintf(stderr, "unix-tools", "unknown tool '%s'", argv[1])
+		usage(stderr)
+		return cli.ExitUsage
+	}
+	return tool(argv[2:])
+}
+
+// usage prints how to invoke the binary and the tools it provides.
+func usage(w io.Writer) {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	fmt.Fprintln(w, "usage: unix-tools TOOL [ARGUMENT]...")
+	fmt.Fprintf(w, "Tools: %s\n", strings.Join(names, ", "))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"
+)
+
+// stubTools replaces every tool with a stub that records its name and
+// arguments, restoring the real tools when the test ends.
+func stubTools(t *testing.T) (called *string, gotArgs *[]string) {
+	t.Helper()
+	original := tools
+	t.Cleanup(func() { tools = original })
+
+	called, gotArgs = new(string), new([]string)
+	tools = make(map[string]cli.Tool, len(original))
+	for name := range original {
+		tools[name] = func(args []string) int {
+			*called, *gotArgs = name, args
+			return 7
+		}
+	}
+	return called, gotArgs
+}
+
+func TestDispatchSubcommand(t *testing.T) {
+	for _, name := range []string{"base64", "basename", "cat", "chmod", "chown", "cmp", "comm", "cp", "cut", "date", "df", "diff", "dirname", "du", "echo", "expand", "fold", "grep", "head", "id", "ls", "md5sum", "mkdir", "mv", "nl", "paste", "pwd", "rev", "rm", "seq", "sha256sum", "sort", "split", "stat", "tac", "tail", "tee", "touch", "tr", "tree", "tsort", "unexpand", "uniq", "wc", "yes"} {
+		called, gotArgs := stubTools(t)
+		var stderr bytes.Buffer
+		status := dispatch([]string{"/usr/local/bin/unix-tools", name, "-x", "file"}, &stderr)
+		if *called != name || !slices.Equal(*gotArgs, []string{"-x", "file"}) || status != 7 {
+			t.Errorf("Expected %s to run with [-x file] but got %q with %q (status %d)", name, *called, *gotArgs, status)
+		}
+		if stderr.Len() != 0 {
+			t.Errorf("Unexpected stderr output for %s: %q", name, stderr.String())
+		}
+	}
+}
+
+func TestDispatchProgramName(t *testing.T) {
+	called, gotArgs := stubTools(t)
+	var stderr bytes.Buffer
+	dispatch([]string{"/bin/ls", "-l", "dir"}, &stderr)
+	if *called != "ls" || !slices.Equal(*gotArgs, []string{"-l", "dir"}) {
+		t.Errorf("Expected ls to run with [-l dir] but got %q with %q", *called, *gotArgs)
+	}
+}
+
+func TestDispatchUnknown(t *testing.T) {
+	called, _ := stubTools(t)
+	for _, argv := range [][]string{{"unix-tools"}, {"unix-tools", "nope"}} {
+		var stderr bytes.Buffer
+		if status := dispatch(argv, &stderr); status != cli.ExitUsage {
+			t.Errorf("Expected exit status %d for %q but got %d", cli.ExitUsage, argv, status)
+		}
+		if !strings.Contains(stderr.String(), "usage: unix-tools TOOL") {
+			t.Errorf("Expected usage for %q but got %q", argv, stderr.String())
+		}
+		if *called != "" {
+			t.Errorf("Expected no tool to run for %q but %s did", argv, *called)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package base64 implements the functionality for the "base64" Unix tool.
+package base64
+
+import (
+	"bufio"           // For buffering the output.
+	"encoding/base64" // For the streaming encoder and decoder.
+	"errors"          // For defining and recognizing decoding errors.
+	"flag"            // Used to parse command-line flags.
+	"io"              // Provides the reader and writer abstractions used for input and output.
+	"os"              // For interacting with the file system and OS I/O.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the base64 functionality.
+// It encodes the file (or stdin) to base64, or decodes it with -d, and
+// returns the exit status: 0 on success, 1 if the input could not be read
+// or was not valid base64, or 2 for a usage error.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "base64".
+	fset := flag.NewFlagSet("base64", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-d" flag to decode rather than encode.
+	decode := fset.Bool("d", false, "decode data")
+	// Define the "-w" flag to set where encoded lines wrap.
+	cols := fset.Int("w", 76, "wrap encoded lines after `COLS` characters; 0 disables wrapping")
+	fset.Parse(args)
+
+	if *cols < 0 {
+		cli.Fprintf(stderr, "base64", "invalid wrap size: '%d'", *cols)
+		return cli.ExitUsage
+	}
+	if fset.NArg() > 1 {
+		cli.Fprintf(stderr, "base64", "extra operand '%s'", fset.Arg(1))
+		return cli.ExitUsage
+	}
+
+	// Read standard input when no file is given.
+	file := "-"
+	if fset.NArg() == 1 {
+		file = fset.Arg(0)
+	}
+	in := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			cli.Fprintf(stderr, "base64", "%s: %v", file, cli.Unwrap(err))
+			return cli.ExitFailure
+		}
+		defer f.Close()
+		in = f
+	}
+
+	out := bufio.NewWriter(stdout)
+	var err error
+	if *decode {
+		err = decodeStream(out, in)
+	} else {
+		err = encodeStream(out, in, *cols)
+	}
+	if err != nil {
+		out.Flush()
+		if errors.Is(err, errInvalidInput) {
+			cli.Fprintf(stderr, "base64", "%v", err)
+		} else {
+			cli.Fprintf(stderr, "base64", "%s: %v", file, cli.Unwrap(err))
+		}
+		return cli.ExitFailure
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "base64", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// encodeStream writes r to w in base64, in lines of cols characters, or
+// on a single line if cols is 0. Non-empty output ends with a newline
+// unless wrapping is disabled.
+func encodeStream(w io.Writer, r io.Reader, cols int) error {
+	lw := &lineWriter{w: w, cols: cols}
+	enc := base64.NewEncoder(base64.StdEncoding, lw)
+	if _, err := io.Copy(enc, r); err != nil {
+		return err
+	}
+	// Closing flushes the final, possibly padded, group.
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if lw.col > 0 {
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return nil
+}
+
+// errInvalidInput reports input that is not valid base64.
+var errInvalidInput = errors.New("invalid input")
+
+// decodeStream writes the data encoded in base64 by r to w. Line breaks
+// in the input are ignored. Each group of four characters is decoded on
+// its own, so like GNU base64 decoding carries on after a padded group,
+// and concatenated encodings decode to the concatenated data. Input with
+// bad characters, or cut short so that its last group is incomplete,
+// gives errInvalidInput.
+func decodeStream(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	var group [4]byte
+	var data [3]byte
+	n := 0
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if c == '\n' || c == '\r' {
+			continue
+		}
+		group[n] = c
+		if n++; n < len(group) {
+			continue
+		}
+		n = 0
+		m, err := base64.StdEncoding.Decode(data[:], group[:])
+		if err != nil {
+			return errInvalidInput
+		}
+		if _, err := w.Write(data[:m]); err != nil {
+			return err
+		}
+	}
+	if n > 0 {
+		return errInvalidInput
+	}
+	return nil
+}
+
+// lineWriter passes what is written to it on to w, inserting a newline
+// after every cols bytes. A cols of 0 inserts none.
+type lineWriter struct {
+	w    io.Writer
+	cols int
+	col  int // Bytes written since the last newline.
+}
+
+// Write implements io.Writer.
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	if lw.cols == 0 {
+		return lw.w.Write(p)
+	}
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), lw.cols-lw.col)]
+		n, err := lw.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+		if lw.col += n; lw.col == lw.cols {
+			if _, err := io.WriteString(lw.w, "\n"); err != nil {
+				return written, err
+			}
+			lw.col = 0
+		}
+	}
+	return written, nil
+}
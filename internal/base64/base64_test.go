@@ -0,0 +1,83 @@
+package base64
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{nil, "", ""},
+		{nil, "hello\n", "aGVsbG8K\n"},
+		{[]string{"-w", "4"}, "hello\n", "aGVs\nbG8K\n"},
+		{[]string{"-w", "3"}, "hello\n", "aGV\nsbG\n8K\n"},
+		{[]string{"-w", "0"}, "hello\n", "aGVsbG8K"},
+		{nil, strings.Repeat("x", 60), strings.Repeat("eHh4", 19) + "\n" + "eHh4\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, strings.NewReader(tt.input), &stdout, &stderr); status != 0 {
+			t.Fatalf("base64 %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("base64 %v: Expected %q but got %q", tt.args, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// Every byte value, long enough to wrap over several lines.
+	var input []byte
+	for i := 0; i < 1000; i++ {
+		input = append(input, byte(i))
+	}
+	var encoded, decoded, stderr bytes.Buffer
+	if status := run([]string{"-w", "10"}, bytes.NewReader(input), &encoded, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if status := run([]string{"-d"}, &encoded, &decoded, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if !bytes.Equal(decoded.Bytes(), input) {
+		t.Errorf("Expected %q but got %q", input, decoded.Bytes())
+	}
+
+	// Concatenated encodings, each with its own padding, decode to the
+	// concatenated data.
+	var chunks strings.Builder
+	for _, chunk := range []string{"hello", "hi", "!"} {
+		encoded.Reset()
+		if status := run(nil, strings.NewReader(chunk), &encoded, &stderr); status != 0 {
+			t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+		}
+		chunks.Write(encoded.Bytes())
+	}
+	if chunks.String() != "aGVsbG8=\naGk=\nIQ==\n" {
+		t.Fatalf("Unexpected encoding %q", chunks.String())
+	}
+	decoded.Reset()
+	if status := run([]string{"-d"}, strings.NewReader(chunks.String()), &decoded, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if expected := "hellohi!"; decoded.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, decoded.String())
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	// Bad characters and input cut short are both invalid.
+	for _, input := range []string{"aGVs!bG8K\n", "aGVsbG8"} {
+		var stdout, stderr bytes.Buffer
+		if status := run([]string{"-d"}, strings.NewReader(input), &stdout, &stderr); status != 1 {
+			t.Errorf("%q: Expected status 1 but got %d", input, status)
+		}
+		if expected := "base64: invalid input\n"; stderr.String() != expected {
+			t.Errorf("%q: Expected %q but got %q", input, expected, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package basename implements the functionality for the "basename" Unix tool.
+package basename
+
+import (
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For printing the results.
+	"io"      // Provides the writer abstraction used for output.
+	"os"      // For the standard streams.
+	"strings" // For trimming slashes and suffixes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the basename functionality.
+// It prints NAME with its leading directories, and optionally a SUFFIX,
+// removed, and returns the exit status: 0 on success, or 2 on invalid
+// usage.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "basename".
+	fs := flag.NewFlagSet("basename", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	// Define the "-a" flag to treat every operand as a NAME.
+	multiple := fs.Bool("a", false, "support multiple arguments and treat each as a NAME")
+	// Define the "-s" flag to remove a suffix from every NAME; it implies -a.
+	suffix := fs.String("s", "", "remove a trailing `SUFFIX`; implies -a")
+	fs.Parse(args)
+
+	names := fs.Args()
+	if len(names) == 0 {
+		cli.Fprintf(stderr, "basename", "missing operand")
+		return cli.ExitUsage
+	}
+	if *suffix != "" {
+		*multiple = true
+	}
+	if !*multiple {
+		// In the classic form, a second operand is the suffix.
+		if len(names) > 2 {
+			cli.Fprintf(stderr, "basename", "extra operand '%s'", names[2])
+			return cli.ExitUsage
+		}
+		if len(names) == 2 {
+			*suffix = names[1]
+		}
+		names = names[:1]
+	}
+
+	for _, name := range names {
+		fmt.Fprintln(stdout, basename(name, *suffix))
+	}
+	return cli.ExitSuccess
+}
+
+// basename returns the last component of name as POSIX defines it:
+// trailing slashes are ignored, a name made only of slashes is "/", and
+// suffix is removed unless it is the whole component.
+func basename(name, suffix string) string {
+	if name == "" {
+		return ""
+	}
+	trimmed := strings.TrimRight(name, "/")
+	if trimmed == "" {
+		return "/"
+	}
+	base := trimmed[strings.LastIndexByte(trimmed, '/')+1:]
+	if suffix != "" && base != suffix {
+		base = strings.TrimSuffix(base, suffix)
+	}
+	return base
+}
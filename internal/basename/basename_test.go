@@ -0,0 +1,40 @@
+package basename
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBasename(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"/a/b.txt"}, "b.txt\n"},
+		{[]string{"/a/b.txt", ".txt"}, "b\n"},
+		{[]string{"b.txt", "b.txt"}, "b.txt\n"},
+		{[]string{"dir/sub/"}, "sub\n"},
+		{[]string{"relative"}, "relative\n"},
+		{[]string{"/"}, "/\n"},
+		{[]string{"///"}, "/\n"},
+		{[]string{""}, "\n"},
+		{[]string{"-a", "/x/one", "two/"}, "one\ntwo\n"},
+		{[]string{"-s", ".go", "a/main.go", "b/test.go", "c.txt"}, "main\ntest\nc.txt\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, &stdout, &stderr)
+		if stdout.String() != tt.expected || status != 0 {
+			t.Errorf("basename %q: Expected %q (0) but got %q (%d)", tt.args, tt.expected, stdout.String(), status)
+		}
+	}
+}
+
+func TestExtraOperand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"a", "b", "c"}, &stdout, &stderr)
+	expected := "basename: extra operand 'c'\n"
+	if stderr.String() != expected || status != 2 {
+		t.Errorf("Expected %q (2) but got %q (%d)", expected, stderr.String(), status)
+	}
+}
@@ -2,61 +2,171 @@
 package cat
 
 import (
-	"bufio"   // Provides buffered I/O for efficient reading.
-	"flag"    // Used to parse command-line flags.
-	"fmt"     // For formatted I/O operations.
-	"os"      // For interacting with the file system and OS I/O.
-	"strings" // Provides functions for string manipulation.
+	"bufio"         // Provides buffered I/O for efficient reading.
+	"bytes"         // For locating line breaks in raw input.
+	"compress/gzip" // For transparently reading gzip-compressed input.
+	"errors"        // For recognizing over-long line errors.
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For formatted I/O operations.
+	"io"            // Provides the writer abstraction used for output.
+	"os"            // For interacting with the file system and OS I/O.
+	"strings"       // Provides functions for string manipulation.
 
-	"golang.org/x/term" // For obtaining terminal dimensions.
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+	"golang.org/x/term"                               // For obtaining terminal dimensions.
 )
 
 var (
-	width int // Terminal width, used for formatting output.
+	width       int           // Terminal width, used for formatting output.
+	maxLineSize = 1024 * 1024 // Longest line, in bytes, that cat will read.
 )
 
+const (
+	defaultWidth = 80      // Width assumed when stdout is not a terminal.
+	minWidth     = 20      // Narrowest width the numbered layout is drawn at.
+	stdinLabel   = "stdin" // Name shown in the header when reading standard input.
+)
+
+// options holds the parsed command-line flags for cat.
+type options struct {
+	lineNumbers     bool // -n: print line numbers.
+	squeeze         bool // -s: suppress repeated empty output lines.
+	nonBlank        bool // -b: number only non-empty lines; overrides -n.
+	showEnds        bool // -E: display $ at the end of each line.
+	showTabs        bool // -T: display TAB characters as ^I.
+	showNonPrinting bool // -v: use ^ and M- notation, except for LFD and TAB.
+	unbuffered      bool // -u: flush output after every line.
+	noHeader        bool // -H, --no-header: omit the borders and file banner when numbering.
+}
+
+// numbered reports whether output lines are numbered, by either -n or -b.
+func (o *options) numbered() bool {
+	return o.lineNumbers || o.nonBlank
+}
+
 // Run is the entry point for the cat functionality.
 // It parses flags, determines the source(s) of input (files or stdin),
 // and then prints the file contents (optionally with line numbers).
-func Run(args []string) {
+// It returns the exit status: 0 on success, or 1 if any input failed.
+func Run(args []string) int {
 	var err error
 	// Obtain the terminal dimensions to format the output header.
 	width, _, err = term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
-		// Print error to standard error if terminal size cannot be determined.
-		fmt.Fprintf(os.Stderr, "cat: error getting terminal size: %v\n", err)
-		return
+		// Output is not a terminal (e.g. a pipe), so fall back to a default width.
+		width = defaultWidth
 	}
 
-	// Create a new FlagSet for parsing command-line options specific to "cat".
-	fs := flag.NewFlagSet("cat", flag.ExitOnError)
-	// Define a boolean flag "-n" to indicate if line numbers should be printed.
-	lineNumbers := fs.Bool("n", false, "print line numbers")
-	// Parse the provided arguments according to the defined flags.
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, files := parseArgs(args)
+	return processInputs("cat", files, stdin, stdout, stderr,
+		func(w *bufio.Writer, r io.Reader, name string) error {
+			return printFromReader(w, r, name, &opts)
+		})
+}
+
+// RunReverse is the entry point for the "tac" functionality.
+// It prints the lines of each file (or stdin) in reverse order and returns
+// the exit status: 0 on success, or 1 if any input failed.
+func RunReverse(args []string) int {
+	return runReverse(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// runReverse performs the actual work of RunReverse using the provided streams.
+func runReverse(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "tac".
+	fs := flag.NewFlagSet("tac", flag.ExitOnError)
+	fs.SetOutput(stderr)
 	fs.Parse(args)
+	return processInputs("tac", fs.Args(), stdin, stdout, stderr, printReversed)
+}
+
+// emitFunc writes the contents read from r, labelled name, to w.
+type emitFunc func(w *bufio.Writer, r io.Reader, name string) error
+
+// processInputs passes each named file (or stdin, for "-" or when no files
+// are given) to emit, reporting failures on stderr prefixed with prog.
+// It keeps going past failed files and returns 1 if any of them failed.
+func processInputs(prog string, files []string, stdin io.Reader, stdout, stderr io.Writer, emit emitFunc) int {
+	// All output goes through a buffered writer; it is flushed per line with
+	// -u and otherwise only when a file is done or an error is reported.
+	out := bufio.NewWriter(stdout)
 
-	// Retrieve non-flag arguments, which are interpreted as file names.
-	files := fs.Args()
 	// If no files are provided, read from standard input.
-	for len(files) == 0 {
-		printFromReader(os.Stdin, lineNumbers)
-		return
+	if len(files) == 0 {
+		files = []string{"-"}
 	}
 
-	// Iterate over each provided file name.
+	// Iterate over each provided file name, continuing past failures.
+	status := cli.ExitSuccess
 	for _, file := range files {
-		// Process each file and print its contents.
-		err := printFile(file, lineNumbers)
+		var err error
+		if file == "-" {
+			// Read standard input, labelled with a friendly name.
+			if err = emit(out, stdin, stdinLabel); err != nil {
+				err = fmt.Errorf("%s: %w", stdinLabel, err)
+			}
+		} else {
+			// Process each file and print its contents.
+			err = printFile(out, file, emit)
+		}
 		if err != nil {
-			// If there's an error opening or reading a file, print it to stderr.
-			fmt.Fprintf(os.Stderr, "cat: %v\n", err)
+			// Flush what was printed so far, then report the error on stderr.
+			out.Flush()
+			cli.Fprintf(stderr, prog, "%v", err)
+			status = cli.ExitFailure
 		}
 	}
+
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, prog, "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// parseArgs parses the command-line flags for cat and returns the resulting
+// options along with the remaining non-flag arguments (the file names).
+func parseArgs(args []string) (options, []string) {
+	// Create a new FlagSet for parsing command-line options specific to "cat".
+	fs := flag.NewFlagSet("cat", flag.ExitOnError)
+	var opts options
+	// Define a boolean flag "-n" to indicate if line numbers should be printed.
+	fs.BoolVar(&opts.lineNumbers, "n", false, "print line numbers")
+	// Define a boolean flag "-s" to collapse runs of blank lines into one.
+	fs.BoolVar(&opts.squeeze, "s", false, "suppress repeated empty output lines")
+	// Define a boolean flag "-b" to number only non-blank lines.
+	fs.BoolVar(&opts.nonBlank, "b", false, "number nonempty output lines, overrides -n")
+	// Define a boolean flag "-E" to mark the end of every line with "$".
+	fs.BoolVar(&opts.showEnds, "E", false, "display $ at end of each line")
+	// Define a boolean flag "-T" to render tabs visibly as "^I".
+	fs.BoolVar(&opts.showTabs, "T", false, "display TAB characters as ^I")
+	// Define a boolean flag "-v" to render control and high bytes visibly.
+	fs.BoolVar(&opts.showNonPrinting, "v", false, "use ^ and M- notation, except for LFD and TAB")
+	// Define a boolean flag "-u" to write each line out as soon as it is read.
+	fs.BoolVar(&opts.unbuffered, "u", false, "flush output after every line")
+	// Define "--no-header" (and its short form "-H") to drop the decorative box.
+	fs.BoolVar(&opts.noHeader, "no-header", false, "number lines without the header and footer borders")
+	fs.BoolVar(&opts.noHeader, "H", false, "shorthand for --no-header")
+	// Define a boolean flag "-A" as shorthand for "-vET".
+	showAll := fs.Bool("A", false, "equivalent to -vET")
+	// Parse the provided arguments according to the defined flags.
+	fs.Parse(args)
+	if *showAll {
+		opts.showNonPrinting, opts.showEnds, opts.showTabs = true, true, true
+	}
+
+	// Retrieve non-flag arguments, which are interpreted as file names.
+	return opts, fs.Args()
 }
 
-// printFile opens the specified file, prints its contents to stdout,
-// and optionally adds line numbers. It returns an error if file access fails.
-func printFile(fileName string, lineNumbers *bool) error {
+// printFile opens the specified file and hands its contents to emit for
+// printing to w. It returns an error if file access fails.
+func printFile(w *bufio.Writer, fileName string, emit emitFunc) error {
 	// Open the file in read-only mode.
 	file, err := os.Open(fileName)
 	if err != nil {
@@ -65,60 +175,222 @@ func printFile(fileName string, lineNumbers *bool) error {
 	// Ensure the file is closed after processing to free resources.
 	defer file.Close()
 
+	// Opening a directory succeeds but reading it yields nothing, so reject it.
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		return fmt.Errorf("%s: Is a directory", fileName)
+	}
+
 	// Read from the file and print its contents.
-	printFromReader(file, lineNumbers)
+	if err := emit(w, file, fileName); err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
 	return nil
 }
 
-// printFromReader reads from the provided file and prints its content to stdout.
-// It prints a header with the file's name and optionally prefixes each line with its line number.
-func printFromReader(reader *os.File, lineNumbers *bool) {
-	// Create a new scanner to read the input line by line.
+// printFromReader reads from the provided reader and prints its content to w.
+// It prints a header with the given name and optionally prefixes each line with its line number.
+// It returns any error encountered while reading.
+func printFromReader(w *bufio.Writer, reader io.Reader, name string, opts *options) error {
+	// Transparently decompress gzip data, detected by its magic bytes.
+	reader, err := decompress(reader)
+	if err != nil {
+		return err
+	}
+
+	// Create a new scanner to read the input line by line. Lines are split
+	// on raw bytes so carriage returns and a missing final newline survive.
 	scanner := bufio.NewScanner(reader)
-	if *lineNumbers {
+	scanner.Split(scanRawLines)
+	// Allow lines far longer than the default 64KB, e.g. minified JS or JSON.
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	// Clamp the width so the border and wrap arithmetic below never go negative.
+	cols := max(width, minWidth)
+	if opts.numbered() && !opts.noHeader {
 		// The header includes a border and centers the file name.
-		fmt.Print(
-			strings.Repeat("─", 7), "┬", strings.Repeat("─", width-8), "\n",
+		fmt.Fprint(w,
+			strings.Repeat("─", 7), "┬", strings.Repeat("─", cols-8), "\n",
 			strings.Repeat(" ", 7), "│ File: ",
-			reader.Name(), "\n",
-			strings.Repeat("─", 7), "┼", strings.Repeat("─", width-8), "\n",
+			name, "\n",
+			strings.Repeat("─", 7), "┼", strings.Repeat("─", cols-8), "\n",
 		)
 	}
 
-	lineCounter := 1 // Initialize a counter for line numbering.
-	if *lineNumbers {
-		// Iterate over each line of the input.
-		for scanner.Scan() {
+	lineCounter := 1   // Initialize a counter for line numbering.
+	prevBlank := false // Whether the previously printed line was empty.
+	// Iterate over each line of the input.
+	for scanner.Scan() {
+		line, hasNewline := strings.CutSuffix(scanner.Text(), "\n")
+
+		// With squeezing enabled, skip an empty line that follows another one.
+		if opts.squeeze {
+			blank := line == ""
+			if blank && prevBlank {
+				continue
+			}
+			prevBlank = blank
+		}
+
+		// Apply the display options before the line is laid out.
+		text := decorate(line, opts)
+
+		switch {
+		case !opts.numbered():
+			// Otherwise, simply print the line, keeping a missing final newline missing.
+			w.WriteString(text)
+			if hasNewline {
+				w.WriteByte('\n')
+			}
+
+		case opts.nonBlank && line == "":
+			// With -b, blank lines keep the gutter but get no number.
+			w.WriteString("       │ " + text + "\n")
+
+		default:
 			// If line numbering is enabled, format the output with a fixed width for numbers.
-			// fmt.Printf("%6d │ %s\n", lineCounter, scanner.Text())
-			fmt.Printf("%6d │ ", lineCounter)
+			fmt.Fprintf(w, "%6d │ ", lineCounter)
 
-			for i, t := range scanner.Text() {
-				if i%(width-9) == 0 && i != 0 {
-					println()
-					fmt.Printf("       │ ")
+			// Wrap the text to the space right of the gutter, counting display
+			// columns per rune so multibyte characters are never split.
+			col := 0
+			for _, r := range text {
+				rw := runeWidth(r)
+				if col > 0 && col+rw > cols-9 {
+					w.WriteString("\n       │ ")
+					col = 0
 				}
-				print(string(t))
+				w.WriteRune(r)
+				col += rw
 			}
-
-			fmt.Println()
+			w.WriteByte('\n')
 
 			lineCounter++
 		}
-		// The header includes a border and centers the file name.
-		fmt.Print(
-			strings.Repeat("─", 7), "┴", strings.Repeat("─", width-8), "\n",
+
+		// In unbuffered mode every line is pushed out as soon as it is complete.
+		if opts.unbuffered {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.numbered() && !opts.noHeader {
+		// The footer closes the border opened by the header.
+		fmt.Fprint(w,
+			strings.Repeat("─", 7), "┴", strings.Repeat("─", cols-8), "\n",
 		)
-	} else {
-		// Iterate over each line of the input.
-		for scanner.Scan() {
-			// Otherwise, simply print the line.
-			fmt.Println(scanner.Text())
+	}
+	// Report any error that occurred during scanning to the caller.
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return fmt.Errorf("line exceeds the maximum length of %d bytes", maxLineSize)
 		}
+		return err
+	}
+	return nil
+}
+
+// decorate applies the display flags to a single line of input.
+// Tabs are expanded before wrapping so that "^I" counts as two columns.
+func decorate(line string, opts *options) string {
+	if opts.showNonPrinting {
+		// Render every byte except tabs in caret or meta notation.
+		var b strings.Builder
+		for i := 0; i < len(line); i++ {
+			if line[i] == '\t' {
+				b.WriteByte('\t')
+				continue
+			}
+			b.WriteString(renderNonPrinting(line[i]))
+		}
+		line = b.String()
+	}
+	if opts.showTabs {
+		// Replace each tab with its caret notation.
+		line = strings.ReplaceAll(line, "\t", "^I")
+	}
+	if opts.showEnds {
+		// Mark the end of the line so trailing white space becomes visible.
+		line += "$"
+	}
+	return line
+}
+
+// printReversed reads all lines from r and writes them to w last-to-first,
+// like tac. A final line without a newline is terminated on output so it
+// does not run into the line printed after it.
+func printReversed(w *bufio.Writer, r io.Reader, name string) error {
+	reader, err := decompress(r)
+	if err != nil {
+		return err
+	}
+
+	// Buffer every line of the input before emitting anything.
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(scanRawLines)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
 	}
-	// Check for errors that occurred during scanning.
 	if err := scanner.Err(); err != nil {
-		// Print any read error to stderr.
-		fmt.Fprintf(os.Stderr, "cat: error reading input: %v\n", err)
+		return err
+	}
+
+	for i := len(lines) - 1; i >= 0; i-- {
+		w.WriteString(lines[i])
+		if !strings.HasSuffix(lines[i], "\n") {
+			w.WriteByte('\n')
+		}
+	}
+	return nil
+}
+
+// gzipMagic is the two-byte signature that starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompress returns a reader yielding the decompressed contents of r if it
+// holds gzip data, or the unchanged bytes of r otherwise. Detection uses the
+// magic bytes rather than the file name so it also works on stdin.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// renderNonPrinting returns the visible representation of a byte as used by
+// "cat -v": control characters become ^@ through ^_, DEL becomes ^?, and
+// bytes with the high bit set are shown with an M- prefix.
+func renderNonPrinting(b byte) string {
+	switch {
+	case b >= 128:
+		return "M-" + renderNonPrinting(b-128)
+	case b == 127:
+		return "^?"
+	case b < 32:
+		return "^" + string(rune(b+'@'))
+	default:
+		return string(rune(b))
+	}
+}
+
+// scanRawLines is a bufio.SplitFunc like bufio.ScanLines, except that it
+// keeps the trailing newline in the token and never strips carriage
+// returns, so the caller sees the input bytes unchanged.
+func scanRawLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		// A full line, including its newline.
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF {
+		// A final line without a newline.
+		return len(data), data, nil
 	}
+	// Request more data.
+	return 0, nil, nil
 }
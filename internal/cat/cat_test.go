@@ -0,0 +1,340 @@
+package cat
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+// render runs printFromReader over content with the given options at the
+// default width.
+func render(t *testing.T, content string, opts options) string {
+	t.Helper()
+	return renderAt(t, 80, content, opts)
+}
+
+// renderAt runs printFromReader over content with the given terminal width.
+func renderAt(t *testing.T, cols int, content string, opts options) string {
+	t.Helper()
+	width = cols
+	var buf bytes.Buffer
+	out := bufio.NewWriter(&buf)
+	if err := printFromReader(out, strings.NewReader(content), "fixture.txt", &opts); err != nil {
+		t.Fatalf("printFromReader returned error: %v", err)
+	}
+	out.Flush()
+	return buf.String()
+}
+
+func TestPrintFromReader(t *testing.T) {
+	expected := "one\ntwo\n"
+	if got := render(t, "one\ntwo\n", options{}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestSqueezeBlankLines(t *testing.T) {
+	expected := "a\n\nb\n\n"
+	if got := render(t, "a\n\n\n\nb\n\n\n", options{squeeze: true}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	// Numbered mode must number only the lines that survive squeezing.
+	got := render(t, "a\n\n\n\nb\n", options{squeeze: true, lineNumbers: true})
+	for _, want := range []string{"     1 │ a\n", "     2 │ \n", "     3 │ b\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected output to contain %q but got %q", want, got)
+		}
+	}
+	if strings.Contains(got, "     4 │") {
+		t.Errorf("Expected blank lines to be squeezed but got %q", got)
+	}
+}
+
+func TestNumberNonBlank(t *testing.T) {
+	content := "a\n\nb\n\n\nc\n"
+	expected := "     1 │ a\n       │ \n     2 │ b\n       │ \n       │ \n     3 │ c\n"
+
+	for _, opts := range []options{{nonBlank: true}, {nonBlank: true, lineNumbers: true}} {
+		got := render(t, content, opts)
+		if !strings.Contains(got, expected) {
+			t.Errorf("With %+v expected output to contain %q but got %q", opts, expected, got)
+		}
+	}
+}
+
+func TestShowEnds(t *testing.T) {
+	expected := "foo$\n$\n"
+	if got := render(t, "foo\n\n", options{showEnds: true}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	expected = "     1 │ foo$\n"
+	if got := render(t, "foo\n", options{showEnds: true, lineNumbers: true}); !strings.Contains(got, expected) {
+		t.Errorf("Expected output to contain %q but got %q", expected, got)
+	}
+}
+
+func TestShowTabs(t *testing.T) {
+	expected := "a^Ib^I^Ic\n"
+	if got := render(t, "a\tb\t\tc\n", options{showTabs: true}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	// In numbered mode the two-column "^I" must take part in line wrapping.
+	got := renderAt(t, 20, "\t\t\t\t\t\t\n", options{showTabs: true, lineNumbers: true})
+	if strings.Contains(got, "\t") {
+		t.Errorf("Expected no raw tabs but got %q", got)
+	}
+	expected = "     1 │ ^I^I^I^I^I^\n       │ I\n"
+	if !strings.Contains(got, expected) {
+		t.Errorf("Expected output to contain %q but got %q", expected, got)
+	}
+}
+
+func TestShowNonPrinting(t *testing.T) {
+	expected := "a^@b^?c^M\tM-^AM-a\n"
+	if got := render(t, "a\x00b\x7fc\r\t\x81\xe1\n", options{showNonPrinting: true}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestRenderNonPrinting(t *testing.T) {
+	tests := map[byte]string{
+		0x00: "^@",
+		0x01: "^A",
+		0x1a: "^Z",
+		0x7f: "^?",
+		'x':  "x",
+		0x80: "M-^@",
+		0xff: "M-^?",
+		0xc1: "M-A",
+	}
+	for in, expected := range tests {
+		if got := renderNonPrinting(in); got != expected {
+			t.Errorf("renderNonPrinting(%#x): expected %q but got %q", in, expected, got)
+		}
+	}
+}
+
+func TestMissingFinalNewlinePreserved(t *testing.T) {
+	expected := "one\r\ntwo"
+	if got := render(t, "one\r\ntwo", options{}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestShowAll(t *testing.T) {
+	opts, files := parseArgs([]string{"-A", "file"})
+	if len(files) != 1 || files[0] != "file" {
+		t.Errorf("Expected file operand to be kept but got %q", files)
+	}
+
+	expected := "a^Ib^Ac$\n"
+	if got := render(t, "a\tb\x01c\n", opts); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestNarrowWidthDoesNotPanic(t *testing.T) {
+	for _, w := range []int{0, 5, 8, 9} {
+		got := renderAt(t, w, "a fairly long line of text\n", options{lineNumbers: true})
+		if !strings.Contains(got, "     1 │ ") {
+			t.Errorf("width %d: expected numbered output but got %q", w, got)
+		}
+	}
+}
+
+func TestWrapMultibyte(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{"ééééééééééééé", "     1 │ ééééééééééé\n       │ éé\n"},
+		{"日本語の文字列です", "     1 │ 日本語の文\n       │ 字列です\n"},
+	}
+	for _, tt := range tests {
+		// A width of 20 leaves 11 columns to the right of the gutter.
+		got := renderAt(t, 20, tt.line+"\n", options{lineNumbers: true})
+		if !strings.Contains(got, tt.expected) {
+			t.Errorf("Expected output to contain %q but got %q", tt.expected, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("Expected valid UTF-8 output but got %q", got)
+		}
+	}
+}
+
+func TestStdinHeader(t *testing.T) {
+	width = 80
+	var stdout, stderr bytes.Buffer
+	run([]string{"-n"}, strings.NewReader("hello\n"), &stdout, &stderr)
+
+	got := stdout.String()
+	if !strings.Contains(got, "│ File: stdin\n") {
+		t.Errorf("Expected stdin header but got %q", got)
+	}
+	if strings.Contains(got, "/dev/stdin") {
+		t.Errorf("Expected no device path in header but got %q", got)
+	}
+}
+
+func TestRunMissingFile(t *testing.T) {
+	good := testutil.TempFile(t, "good.txt", "good\n")
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"/nonexistent/file", good}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	if got := stdout.String(); got != "good\n" {
+		t.Errorf("Expected the good file to still be printed but got %q", got)
+	}
+	if !strings.Contains(stderr.String(), "cat: open /nonexistent/file") {
+		t.Errorf("Expected an error for the missing file but got %q", stderr.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{good}, nil, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected exit code 0 but got %d", code)
+	}
+}
+
+func TestRunDirectory(t *testing.T) {
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{dir}, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	expected := "cat: " + dir + ": Is a directory\n"
+	if got := stderr.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestLongLine(t *testing.T) {
+	line := strings.Repeat("x", 100*1024)
+	var stdout, stderr bytes.Buffer
+
+	if code := run(nil, strings.NewReader(line+"\n"), &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+	if got := stdout.String(); got != line+"\n" {
+		t.Errorf("Expected the %d byte line to be printed intact but got %d bytes", len(line)+1, len(got))
+	}
+
+	// A line beyond the configured cap must produce a clear error.
+	oldMax := maxLineSize
+	maxLineSize = 1024
+	defer func() { maxLineSize = oldMax }()
+
+	stdout.Reset()
+	if code := run(nil, strings.NewReader(line+"\n"), &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "line exceeds the maximum length of 1024 bytes") {
+		t.Errorf("Expected a line length error but got %q", stderr.String())
+	}
+}
+
+// countingWriter records how many separate writes reach it.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.writes++
+	return c.Buffer.Write(p)
+}
+
+func TestUnbuffered(t *testing.T) {
+	input := "one\ntwo\nthree\n"
+
+	for _, tt := range []struct {
+		args   []string
+		writes int
+	}{
+		{nil, 1},
+		{[]string{"-u"}, 3},
+	} {
+		var stdout countingWriter
+		var stderr bytes.Buffer
+		if code := run(tt.args, strings.NewReader(input), &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0 but got %d", code)
+		}
+		if got := stdout.String(); got != input {
+			t.Errorf("cat %v: expected %q but got %q", tt.args, input, got)
+		}
+		if stdout.writes != tt.writes {
+			t.Errorf("cat %v: expected %d writes but got %d", tt.args, tt.writes, stdout.writes)
+		}
+	}
+}
+
+func TestNoHeader(t *testing.T) {
+	for _, flag := range []string{"--no-header", "-H"} {
+		opts, _ := parseArgs([]string{"-n", flag})
+		got := render(t, "a\nb\n", opts)
+		expected := "     1 │ a\n     2 │ b\n"
+		if got != expected {
+			t.Errorf("%s: expected %q but got %q", flag, expected, got)
+		}
+		if strings.ContainsAny(got, "┬┴") {
+			t.Errorf("%s: expected no borders but got %q", flag, got)
+		}
+	}
+}
+
+func TestGzipInput(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("access log line 1\naccess log line 2\n"))
+	zw.Close()
+	path := testutil.TempFile(t, "access.log.gz", compressed.String())
+
+	expected := "access log line 1\naccess log line 2\n"
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{path}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	// Compressed data on stdin is detected the same way.
+	stdout.Reset()
+	run(nil, bytes.NewReader(compressed.Bytes()), &stdout, &stderr)
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q from stdin but got %q", expected, got)
+	}
+}
+
+func TestRunReverse(t *testing.T) {
+	for _, input := range []string{"a\nb\nc\n", "a\nb\nc"} {
+		var stdout, stderr bytes.Buffer
+		if code := runReverse(nil, strings.NewReader(input), &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0 but got %d", code)
+		}
+		expected := "c\nb\na\n"
+		if got := stdout.String(); got != expected {
+			t.Errorf("tac %q: expected %q but got %q", input, expected, got)
+		}
+	}
+
+	// Files are reversed one at a time, in the order given.
+	first := testutil.TempFile(t, "first", "1\n2\n")
+	second := testutil.TempFile(t, "second", "3\n4\n")
+	var stdout, stderr bytes.Buffer
+	runReverse([]string{first, second}, nil, &stdout, &stderr)
+	expected := "2\n1\n4\n3\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
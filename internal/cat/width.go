@@ -0,0 +1,37 @@
+package cat
+
+import "unicode" // For identifying combining marks.
+
+// wideRanges lists the Unicode ranges whose characters occupy two terminal
+// columns (East Asian Wide and Fullwidth characters, plus common emoji).
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},   // Hangul Jamo initial consonants
+	{0x2E80, 0x303E},   // CJK radicals, Kangxi radicals, CJK symbols and punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, Bopomofo, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK Unified Ideographs Extension A
+	{0x4E00, 0x9FFF},   // CJK Unified Ideographs
+	{0xA000, 0xA4CF},   // Yi syllables and radicals
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x1F300, 0x1F64F}, // Miscellaneous symbols, pictographs, and emoticons
+	{0x1F900, 0x1F9FF}, // Supplemental symbols and pictographs
+	{0x20000, 0x2FFFD}, // CJK Unified Ideographs Extension B and beyond
+	{0x30000, 0x3FFFD}, // CJK Unified Ideographs Extension G and beyond
+}
+
+// runeWidth returns the number of terminal columns r occupies:
+// 0 for combining marks, 2 for wide characters, and 1 otherwise.
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me) {
+		return 0
+	}
+	for _, wr := range wideRanges {
+		if r >= wr.lo && r <= wr.hi {
+			return 2
+		}
+	}
+	return 1
+}
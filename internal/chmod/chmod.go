@@ -0,0 +1,116 @@
+// Package chmod implements the functionality for the "chmod" Unix tool.
+package chmod
+
+import (
+	"flag"          // Used to parse command-line flags.
+	"io"            // Provides the writer abstraction used for diagnostics.
+	"io/fs"         // For the directory entries and file modes used by the walk.
+	"os"            // For changing file modes.
+	"path/filepath" // For walking directory trees with -R.
+	"strings"       // For recognizing modes that look like flags.
+	"sync"          // For reading the umask at most once.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the chmod functionality.
+// It changes the mode of each file to MODE, which is octal (755) or
+// symbolic (u+x,go-w), and returns the exit status: 0 on success, 1 if
+// any mode could not be changed, or 2 for a usage error.
+func Run(args []string) int {
+	return run(args, os.Stderr)
+}
+
+// run performs the actual work of Run, reporting errors to stderr.
+func run(args []string, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "chmod".
+	fset := flag.NewFlagSet("chmod", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-R" flag to change directories and their contents.
+	recursive := fset.Bool("R", false, "change files and directories recursively")
+	fset.Parse(separateMode(args))
+
+	switch fset.NArg() {
+	case 0:
+		cli.Fprintf(stderr, "chmod", "missing operand")
+		return cli.ExitUsage
+	case 1:
+		cli.Fprintf(stderr, "chmod", "missing operand after '%s'", fset.Arg(0))
+		return cli.ExitUsage
+	}
+	change, err := parseMode(fset.Arg(0))
+	if err != nil {
+		cli.Fprintf(stderr, "chmod", "invalid mode: '%s'", fset.Arg(0))
+		return cli.ExitUsage
+	}
+
+	umask := sync.OnceValue(currentUmask)
+	status := cli.ExitSuccess
+	for _, file := range fset.Args()[1:] {
+		if !changeMode(file, change, *recursive, umask, stderr) {
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// separateMode returns args with "--" inserted before a mode such as
+// "-w" or "-x,u+r", so that the flag parser takes it, and everything after
+// it, as operands rather than flags.
+func separateMode(args []string) []string {
+	for i, arg := range args {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return args
+		}
+		if _, err := parseMode(arg); err == nil {
+			return append(append(args[:i:i], "--"), args[i:]...)
+		}
+	}
+	return args
+}
+
+// changeMode applies change to file and, with recursive, to everything
+// below it. Symbolic links met on the way are not followed. The result is
+// false if anything could not be changed.
+func changeMode(file string, change *modeChange, recursive bool, umask func() uint32, stderr io.Writer) bool {
+	info, err := os.Stat(file)
+	if err != nil {
+		cli.Fprintf(stderr, "chmod", "cannot access '%s': %v", file, cli.Unwrap(err))
+		return false
+	}
+	ok := setMode(file, info, change, umask, stderr)
+	if !recursive || !info.IsDir() {
+		return ok
+	}
+	filepath.WalkDir(file, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			cli.Fprintf(stderr, "chmod", "cannot read directory '%s': %v", path, cli.Unwrap(err))
+			ok = false
+			return nil
+		}
+		// The root was changed already, and links are left alone.
+		if path == file || entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			cli.Fprintf(stderr, "chmod", "cannot access '%s': %v", path, cli.Unwrap(err))
+			ok = false
+			return nil
+		}
+		if !setMode(path, info, change, umask, stderr) {
+			ok = false
+		}
+		return nil
+	})
+	return ok
+}
+
+// setMode changes the mode of path, whose current metadata is info.
+func setMode(path string, info fs.FileInfo, change *modeChange, umask func() uint32, stderr io.Writer) bool {
+	if err := os.Chmod(path, change.apply(info.Mode(), umask)); err != nil {
+		cli.Fprintf(stderr, "chmod", "changing permissions of '%s': %v", path, cli.Unwrap(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package chmod
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	umask := func() uint32 { return 0o022 }
+	tests := []struct {
+		mode     string
+		from     os.FileMode
+		expected os.FileMode
+	}{
+		{"755", 0o600, 0o755},
+		{"4750", 0o644, os.ModeSetuid | 0o750},
+		{"u+x", 0o644, 0o744},
+		{"go-w", 0o666, 0o644},
+		{"a=r", 0o755, 0o444},
+		{"u=rwx,g=rx,o=", 0o000, 0o750},
+		{"+x", 0o644, 0o755},
+		{"+w", 0o444, 0o644}, // The umask keeps group and others from write access.
+		{"a+w", 0o444, 0o666},
+		{"u+r-w", 0o200, 0o400},
+		{"a+X", 0o644, 0o644},
+		{"a+X", 0o744, 0o755},
+		{"g=u", 0o640, 0o660},
+		{"u+s,o+t", 0o755, os.ModeSetuid | os.ModeSticky | 0o755},
+		{"ug-s", os.ModeSetuid | os.ModeSetgid | 0o755, 0o755},
+	}
+	for _, tt := range tests {
+		change, err := parseMode(tt.mode)
+		if err != nil {
+			t.Errorf("parseMode(%q): unexpected error %v", tt.mode, err)
+			continue
+		}
+		if got := change.apply(tt.from, umask); got != tt.expected {
+			t.Errorf("%s on %v: Expected %v but got %v", tt.mode, tt.from, tt.expected, got)
+		}
+	}
+
+	// X also grants search permission on directories.
+	change, _ := parseMode("a+X")
+	if got := change.apply(os.ModeDir|0o644, umask); got != 0o755 {
+		t.Errorf("a+X on a directory: Expected %v but got %v", os.FileMode(0o755), got)
+	}
+}
+
+func TestInvalidMode(t *testing.T) {
+	for _, mode := range []string{"", "8", "77777", "u", "u+q", "x+r", "u+r,", "+rwu"} {
+		if _, err := parseMode(mode); err == nil {
+			t.Errorf("Expected an error for mode %q", mode)
+		}
+	}
+	var stderr bytes.Buffer
+	if status := run([]string{"u+q", "file"}, &stderr); status != 2 {
+		t.Errorf("Expected status 2 but got %d", status)
+	}
+	if expected := "chmod: invalid mode: 'u+q'\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Permission bits do not apply on Windows")
+	}
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	file := filepath.Join(sub, "file")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("Failed to create %s: %v", sub, err)
+	}
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", file, err)
+	}
+
+	steps := []struct {
+		args []string
+		sub  os.FileMode
+		file os.FileMode
+	}{
+		{[]string{"600", file}, 0o755, 0o600},
+		{[]string{"-w", file}, 0o755, 0o400}, // A mode that looks like a flag.
+		{[]string{"-R", "go-rx,u+w", sub}, 0o700, 0o600},
+		{[]string{"-R", "a+rX", sub}, 0o755, 0o644},
+	}
+	for _, step := range steps {
+		var stderr bytes.Buffer
+		if status := run(step.args, &stderr); status != 0 {
+			t.Fatalf("chmod %v: Expected status 0 but got %d: %s", step.args, status, stderr.String())
+		}
+		for path, expected := range map[string]os.FileMode{sub: step.sub, file: step.file} {
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("Failed to stat %s: %v", path, err)
+			}
+			if perm := info.Mode().Perm(); perm != expected {
+				t.Errorf("chmod %v: Expected %s to have mode %v but got %v", step.args, path, expected, perm)
+			}
+		}
+	}
+}
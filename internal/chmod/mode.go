@@ -0,0 +1,172 @@
+package chmod
+
+import (
+	"errors"  // For the error reported for malformed modes.
+	"os"      // For file modes.
+	"strconv" // For parsing octal modes.
+	"strings" // For splitting symbolic modes into clauses.
+
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo" // For converting between octal and file modes.
+)
+
+// errInvalidMode reports a mode that is neither octal nor symbolic.
+var errInvalidMode = errors.New("invalid mode")
+
+// Permission bits in their traditional Unix octal positions.
+const (
+	permRead   = 0o444  // r: read, for each class.
+	permWrite  = 0o222  // w: write, for each class.
+	permExec   = 0o111  // x: execute or search, for each class.
+	permSetID  = 0o6000 // s: setuid and setgid.
+	permSticky = 0o1000 // t: the sticky bit.
+	permAll    = 0o7777 // Every bit chmod can change.
+)
+
+// classBits maps each "who" letter to the bits it selects.
+var classBits = map[byte]uint32{
+	'u': 0o4700,
+	'g': 0o2070,
+	'o': 0o1007,
+	'a': permAll,
+}
+
+// action is one operator of a symbolic clause together with its operand,
+// e.g. "+x" or "=u".
+type action struct {
+	op    byte   // '+', '-' or '='.
+	perms uint32 // Bits named by r, w, x, s and t.
+	exec  bool   // X: execute, if the file is a directory or executable by anyone.
+	copy  byte   // 'u', 'g' or 'o' to copy that class's current bits, or 0.
+}
+
+// clause is one comma-separated part of a symbolic mode, e.g. "go-w".
+type clause struct {
+	who     uint32 // Bits selected by the who letters, or 0 if none were given.
+	actions []action
+}
+
+// modeChange is a parsed mode operand, which either sets the mode to an
+// octal value or applies symbolic clauses to the current mode.
+type modeChange struct {
+	octal   bool     // Whether the mode was given in octal.
+	bits    uint32   // The octal mode.
+	clauses []clause // The symbolic clauses, applied in order.
+}
+
+// parseMode parses an octal mode such as 755, or a symbolic mode made of
+// comma-separated clauses such as "u+x,go-w" or "a=r".
+func parseMode(s string) (*modeChange, error) {
+	if s != "" && strings.Trim(s, "01234567") == "" {
+		bits, err := strconv.ParseUint(s, 8, 32)
+		if err != nil || bits > permAll {
+			return nil, errInvalidMode
+		}
+		return &modeChange{octal: true, bits: uint32(bits)}, nil
+	}
+	change := &modeChange{}
+	for _, part := range strings.Split(s, ",") {
+		c, err := parseClause(part)
+		if err != nil {
+			return nil, err
+		}
+		change.clauses = append(change.clauses, c)
+	}
+	return change, nil
+}
+
+// parseClause parses one symbolic clause: any who letters followed by one
+// or more operators, each with its permission letters or a class to copy.
+func parseClause(s string) (clause, error) {
+	var c clause
+	i := 0
+	for ; i < len(s) && classBits[s[i]] != 0; i++ {
+		c.who |= classBits[s[i]]
+	}
+	if i == len(s) {
+		return c, errInvalidMode // At least one operator is required.
+	}
+	for i < len(s) {
+		a := action{op: s[i]}
+		if a.op != '+' && a.op != '-' && a.op != '=' {
+			return c, errInvalidMode
+		}
+		i++
+		if i < len(s) && (s[i] == 'u' || s[i] == 'g' || s[i] == 'o') {
+			a.copy = s[i]
+			i++
+		} else {
+			for ; i < len(s) && strings.IndexByte("+-=", s[i]) < 0; i++ {
+				switch s[i] {
+				case 'r':
+					a.perms |= permRead
+				case 'w':
+					a.perms |= permWrite
+				case 'x':
+					a.perms |= permExec
+				case 'X':
+					a.exec = true
+				case 's':
+					a.perms |= permSetID
+				case 't':
+					a.perms |= permSticky
+				default:
+					return c, errInvalidMode
+				}
+			}
+		}
+		c.actions = append(c.actions, a)
+	}
+	return c, nil
+}
+
+// apply returns the permissions that mode has after the change. umask is
+// consulted only by clauses without who letters, which, like "+x", leave
+// the bits set in the umask alone.
+func (m *modeChange) apply(mode os.FileMode, umask func() uint32) os.FileMode {
+	if m.octal {
+		return fileinfo.FromOctal(m.bits)
+	}
+	bits := fileinfo.Octal(mode)
+	for _, c := range m.clauses {
+		who := c.who
+		if who == 0 {
+			who = permAll &^ umask()
+		}
+		for _, a := range c.actions {
+			perms := a.perms
+			if a.exec && (mode.IsDir() || bits&permExec != 0) {
+				perms |= permExec
+			}
+			if a.copy != 0 {
+				perms = classPerms(bits, a.copy) * permExec
+			}
+			perms &= who
+			switch a.op {
+			case '+':
+				bits |= perms
+			case '-':
+				bits &^= perms
+			case '=':
+				clear := c.who
+				if clear == 0 {
+					clear = permAll
+				}
+				bits = bits&^clear | perms
+			}
+		}
+	}
+	return fileinfo.FromOctal(bits)
+}
+
+// classPerms returns the read, write and execute bits that bits grants to
+// the class named by the letter u, g or o, as a value from 0 to 7.
+func classPerms(bits uint32, class byte) uint32 {
+	switch class {
+	case 'u':
+		return bits >> 6 & 7
+	case 'g':
+		return bits >> 3 & 7
+	default:
+		return bits & 7
+	}
+}
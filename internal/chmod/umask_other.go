@@ -0,0 +1,8 @@
+//go:build !unix
+
+package chmod
+
+// currentUmask returns an empty mask on platforms without a umask.
+func currentUmask() uint32 {
+	return 0
+}
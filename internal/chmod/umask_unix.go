@@ -0,0 +1,13 @@
+//go:build unix
+
+package chmod
+
+import "syscall" // To read the process's umask.
+
+// currentUmask returns the file mode creation mask of the process. The
+// mask can only be read by replacing it, so it is restored at once.
+func currentUmask() uint32 {
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return uint32(mask)
+}
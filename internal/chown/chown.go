@@ -0,0 +1,217 @@
+// Package chown implements the functionality for the "chown" Unix tool.
+package chown
+
+import (
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For formatted I/O operations.
+	"io"            // Provides the writer abstraction used for output.
+	"io/fs"         // For walking directory trees.
+	"os"            // For changing ownership and reading file metadata.
+	"os/user"       // To resolve user and group names to IDs.
+	"path/filepath" // For recursive directory traversal.
+	"strconv"       // For parsing numeric user and group IDs.
+	"strings"       // Provides functions for string manipulation.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// options holds the parsed command-line flags for chown.
+type options struct {
+	recursive bool // -R: operate on directories and their contents.
+	noDeref   bool // -h: change symbolic links themselves instead of their targets.
+	verbose   bool // -v: report every file processed.
+	changes   bool // -c: report only files whose ownership actually changed.
+}
+
+// owner is a requested user/group pair. A value of -1 leaves the ID unchanged.
+type owner struct {
+	uid, gid int
+}
+
+// Run is the entry point for the chown functionality.
+// It returns 0 if every file was updated and 1 if any operation failed.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "chown".
+	fset := flag.NewFlagSet("chown", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	fset.BoolVar(&opts.recursive, "R", false, "operate on files and directories recursively")
+	fset.BoolVar(&opts.noDeref, "h", false, "affect symbolic links instead of any referenced file")
+	fset.BoolVar(&opts.verbose, "v", false, "output a diagnostic for every file processed")
+	fset.BoolVar(&opts.changes, "c", false, "like verbose but report only when a change is made")
+	fset.Parse(args)
+
+	if fset.NArg() < 2 {
+		cli.Fprintf(stderr, "chown", "missing operand")
+		return cli.ExitUsage
+	}
+
+	want, err := parseOwner(fset.Arg(0))
+	if err != nil {
+		cli.Fprintf(stderr, "chown", "%v", err)
+		return 1
+	}
+
+	// Process every file, remembering whether any of them failed.
+	status := 0
+	for _, name := range fset.Args()[1:] {
+		if !opts.recursive {
+			if !apply(name, want, opts.noDeref, &opts, stdout, stderr) {
+				status = 1
+			}
+			continue
+		}
+		err := filepath.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				cli.Fprintf(stderr, "chown", "cannot access '%s': %v", path, cli.Unwrap(err))
+				status = 1
+				return nil // Keep going with the rest of the tree.
+			}
+			// Like GNU chown's default -P, only the operand itself is
+			// dereferenced; a symlink met during the walk is changed
+			// itself, never the file it points to.
+			noDeref := opts.noDeref || (path != name && d.Type()&fs.ModeSymlink != 0)
+			if !apply(path, want, noDeref, &opts, stdout, stderr) {
+				status = 1
+			}
+			return nil
+		})
+		if err != nil {
+			cli.Fprintf(stderr, "chown", "%v", err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// parseOwner parses an ownership specification of the form USER,
+// USER:GROUP, USER: (the user's login group) or :GROUP.
+func parseOwner(spec string) (owner, error) {
+	want := owner{uid: -1, gid: -1}
+	userPart, groupPart, hasColon := strings.Cut(spec, ":")
+
+	if userPart == "" && groupPart == "" {
+		return want, fmt.Errorf("invalid spec: '%s'", spec)
+	}
+
+	if userPart != "" {
+		uid, loginGid, err := lookupUser(userPart)
+		if err != nil {
+			return want, err
+		}
+		want.uid = uid
+		// "USER:" with an empty group means the user's login group.
+		if hasColon && groupPart == "" {
+			want.gid = loginGid
+		}
+	}
+
+	if groupPart != "" {
+		gid, err := lookupGroup(groupPart)
+		if err != nil {
+			return want, err
+		}
+		want.gid = gid
+	}
+	return want, nil
+}
+
+// lookupUser resolves a user name or numeric ID, returning the UID and the
+// user's primary group ID (or -1 when the user is given numerically and
+// unknown to the system).
+func lookupUser(name string) (int, int, error) {
+	if u, err := user.Lookup(name); err == nil {
+		uid, _ := strconv.Atoi(u.Uid)
+		gid, _ := strconv.Atoi(u.Gid)
+		return uid, gid, nil
+	}
+	if uid, err := strconv.Atoi(name); err == nil && uid >= 0 {
+		// Numeric IDs are accepted even if no such user exists.
+		if u, err := user.LookupId(name); err == nil {
+			gid, _ := strconv.Atoi(u.Gid)
+			return uid, gid, nil
+		}
+		return uid, -1, nil
+	}
+	return -1, -1, fmt.Errorf("invalid user: '%s'", name)
+}
+
+// lookupGroup resolves a group name or numeric ID to a GID.
+func lookupGroup(name string) (int, error) {
+	if g, err := user.LookupGroup(name); err == nil {
+		gid, _ := strconv.Atoi(g.Gid)
+		return gid, nil
+	}
+	if gid, err := strconv.Atoi(name); err == nil && gid >= 0 {
+		return gid, nil
+	}
+	return -1, fmt.Errorf("invalid group: '%s'", name)
+}
+
+// apply changes the ownership of a single path and reports it as requested
+// by the verbosity flags. With noDeref a symbolic link is changed itself
+// rather than the file it points to. It returns false if the change failed.
+func apply(path string, want owner, noDeref bool, opts *options, stdout, stderr io.Writer) bool {
+	// Look at the link itself when asked to, otherwise at what it points to.
+	stat := os.Stat
+	chown := os.Chown
+	if noDeref {
+		stat = os.Lstat
+		chown = os.Lchown
+	}
+
+	info, err := stat(path)
+	if err != nil {
+		cli.Fprintf(stderr, "chown", "cannot access '%s': %v", path, cli.Unwrap(err))
+		return false
+	}
+	oldUID, oldGID, known := fileOwner(info)
+
+	if err := chown(path, want.uid, want.gid); err != nil {
+		cli.Fprintf(stderr, "chown", "changing ownership of '%s': %v", path, cli.Unwrap(err))
+		return false
+	}
+
+	if !opts.verbose && !opts.changes {
+		return true
+	}
+
+	// Work out the resulting ownership for the report.
+	newUID, newGID := oldUID, oldGID
+	if want.uid >= 0 {
+		newUID = want.uid
+	}
+	if want.gid >= 0 {
+		newGID = want.gid
+	}
+
+	changed := !known || newUID != oldUID || newGID != oldGID
+	switch {
+	case changed && known:
+		fmt.Fprintf(stdout, "changed ownership of '%s' from %s to %s\n",
+			path, describe(oldUID, oldGID), describe(newUID, newGID))
+	case changed:
+		fmt.Fprintf(stdout, "changed ownership of '%s' to %s\n", path, describe(newUID, newGID))
+	case opts.verbose:
+		fmt.Fprintf(stdout, "ownership of '%s' retained as %s\n", path, describe(newUID, newGID))
+	}
+	return true
+}
+
+// describe formats a UID/GID pair as user:group, preferring names over numbers.
+func describe(uid, gid int) string {
+	userName := strconv.Itoa(uid)
+	if u, err := user.LookupId(userName); err == nil {
+		userName = u.Username
+	}
+	groupName := strconv.Itoa(gid)
+	if g, err := user.LookupGroupId(groupName); err == nil {
+		groupName = g.Name
+	}
+	return userName + ":" + groupName
+}
@@ -0,0 +1,168 @@
+package chown
+
+import (
+	"bytes"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// currentIDs returns the UID and GID of the running user, which the user
+// is always permitted to assign to their own files.
+func currentIDs(t *testing.T) (string, string) {
+	t.Helper()
+	return strconv.Itoa(os.Getuid()), strconv.Itoa(os.Getgid())
+}
+
+func TestParseOwner(t *testing.T) {
+	uid, gid := currentIDs(t)
+	u, _ := strconv.Atoi(uid)
+	g, _ := strconv.Atoi(gid)
+
+	tests := []struct {
+		spec     string
+		expected owner
+	}{
+		{uid, owner{uid: u, gid: -1}},
+		{uid + ":" + gid, owner{uid: u, gid: g}},
+		{":" + gid, owner{uid: -1, gid: g}},
+	}
+	for _, tt := range tests {
+		got, err := parseOwner(tt.spec)
+		if err != nil {
+			t.Errorf("parseOwner(%q) returned error: %v", tt.spec, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("parseOwner(%q): expected %+v but got %+v", tt.spec, tt.expected, got)
+		}
+	}
+
+	for _, spec := range []string{":", "no-such-user-xyz", ":no-such-group-xyz"} {
+		if _, err := parseOwner(spec); err == nil {
+			t.Errorf("parseOwner(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestRunOwnIDsByName(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("Cannot determine current user: %v", err)
+	}
+	group, err := user.LookupGroupId(current.Gid)
+	if err != nil {
+		t.Skipf("Cannot determine current group: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-v", current.Username + ":" + group.Name, path}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+	expected := "ownership of '" + path + "' retained as " + current.Username + ":" + group.Name + "\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestRunRecursive(t *testing.T) {
+	uid, gid := currentIDs(t)
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "file"), nil, 0o644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-R", "-v", uid + ":" + gid, dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+	if n := strings.Count(stdout.String(), "retained as"); n != 3 {
+		t.Errorf("Expected 3 files to be processed but got %d in %q", n, stdout.String())
+	}
+
+	// -c only reports actual changes, so nothing should be printed.
+	stdout.Reset()
+	run([]string{"-R", "-c", uid + ":" + gid, dir}, &stdout, &stderr)
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no output with -c but got %q", stdout.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	uid, _ := currentIDs(t)
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{uid, "/nonexistent/file"}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for a missing file but got %d", code)
+	}
+	if code := run([]string{"no-such-user-xyz", "."}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for an unknown user but got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "invalid user") {
+		t.Errorf("Expected an invalid user error but got %q", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{uid}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for a missing operand but got %d", code)
+	}
+	if expected := "chown: missing operand\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
+
+func TestRunRecursiveSymlinks(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "tree")
+	outside := filepath.Join(root, "outside")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(outside, nil, 0o644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(filepath.Join("..", "outside"), link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	if err := os.Symlink("missing", filepath.Join(dir, "dangling")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	// Only root may give files away; anyone else keeps their own IDs,
+	// which still shows that dangling links are not followed.
+	uid, gid := currentIDs(t)
+	if os.Getuid() == 0 {
+		uid, gid = "1234", "1234"
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-R", uid + ":" + gid, dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+	if os.Getuid() != 0 {
+		return
+	}
+	// The links are changed themselves, and the file outside the tree is
+	// left alone.
+	for path, expected := range map[string]int{link: 1234, outside: 0} {
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", path, err)
+		}
+		if uid, gid, ok := fileOwner(info); ok && (uid != expected || gid != expected) {
+			t.Errorf("Expected %s to be owned by %d:%d but got %d:%d", path, expected, expected, uid, gid)
+		}
+	}
+}
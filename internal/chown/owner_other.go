@@ -0,0 +1,10 @@
+//go:build !unix
+
+package chown
+
+import "os" // For the FileInfo type.
+
+// fileOwner reports that ownership is unknown on platforms without Unix IDs.
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	return -1, -1, false
+}
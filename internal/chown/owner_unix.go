@@ -0,0 +1,17 @@
+//go:build unix
+
+package chown
+
+import (
+	"os"      // For the FileInfo type.
+	"syscall" // To access the owner fields of the underlying stat structure.
+)
+
+// fileOwner returns the UID and GID recorded in info.
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
@@ -0,0 +1,47 @@
+// Package cli provides the conventions shared by the command-line tools:
+// the signature of a tool's entry point, the exit statuses it reports and
+// the way it prints diagnostics.
+package cli
+
+import (
+	"errors" // For finding the errors wrapped by the os package.
+	"fmt"    // For formatting diagnostics.
+	"io"     // For the writer diagnostics are printed to.
+	"io/fs"  // For the PathError type.
+	"os"     // For the LinkError type.
+)
+
+// Tool is the entry point of a command-line tool. It runs the tool with
+// the arguments that follow the program name and returns the process exit
+// status, leaving the call to os.Exit to the caller.
+type Tool func(args []string) int
+
+// Exit statuses shared by the tools. Tools with richer conventions, such
+// as cmp reporting 1 for "files differ", document their own meanings.
+const (
+	ExitSuccess = 0 // Everything that was asked for was done.
+	ExitFailure = 1 // Some operation failed, e.g. a file could not be read.
+	ExitUsage   = 2 // The command line was invalid.
+)
+
+// Fprintf prints a diagnostic for the program prog to w, in the form
+// "prog: message" followed by a newline. Tools take their output streams
+// as parameters, so they report errors on the stream they were given.
+func Fprintf(w io.Writer, prog, format string, args ...any) {
+	fmt.Fprintf(w, "%s: %s\n", prog, fmt.Sprintf(format, args...))
+}
+
+// Unwrap strips the operation and paths that the os package adds to err,
+// leaving a message such as "no such file or directory" for diagnostics
+// that name the file themselves.
+func Unwrap(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		return linkErr.Err
+	}
+	return err
+}
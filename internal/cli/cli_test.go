@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestFprintf(t *testing.T) {
+	var buf bytes.Buffer
+	Fprintf(&buf, "ls", "cannot access '%s': %v", "missing", errors.New("no such file or directory"))
+	expected := "ls: cannot access 'missing': no such file or directory\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, buf.String())
+	}
+}
+
+func TestFprintfLiteralPercent(t *testing.T) {
+	var buf bytes.Buffer
+	Fprintf(&buf, "cat", "%s", "100% done")
+	expected := "cat: 100% done\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, buf.String())
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	cause := errors.New("no such file or directory")
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{&fs.PathError{Op: "open", Path: "missing", Err: cause}, cause},
+		{fmt.Errorf("copying: %w", &fs.PathError{Op: "open", Path: "missing", Err: cause}), cause},
+		{&os.LinkError{Op: "rename", Old: "a", New: "b", Err: cause}, cause},
+		{cause, cause},
+	}
+	for _, tt := range tests {
+		if got := Unwrap(tt.err); got != tt.expected {
+			t.Errorf("Unwrap(%v): Expected %v but got %v", tt.err, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package cmp implements the functionality for the "cmp" Unix tool.
+package cmp
+
+import (
+	"bufio" // Provides buffered readers so both inputs are streamed efficiently.
+	"flag"  // Used to parse command-line flags.
+	"fmt"   // For formatted I/O operations.
+	"io"    // Provides the reader and writer abstractions used for input and output.
+	"os"    // For interacting with the file system and OS I/O.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Exit statuses reported by cmp, matching coreutils.
+const (
+	exitSame    = 0 // The inputs are identical.
+	exitDiffer  = 1 // The inputs differ.
+	exitTrouble = 2 // An error prevented the comparison.
+)
+
+// options holds the parsed command-line flags for cmp.
+type options struct {
+	silent  bool  // -s: print nothing, only report through the exit status.
+	verbose bool  // -l: list every differing byte instead of stopping at the first.
+	limit   int64 // -n: compare at most this many bytes; negative means no limit.
+}
+
+// Run is the entry point for the cmp functionality.
+// It returns 0 if the files are identical, 1 if they differ, and 2 on error.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "cmp".
+	fs := flag.NewFlagSet("cmp", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	var opts options
+	fs.BoolVar(&opts.silent, "s", false, "suppress all normal output")
+	fs.BoolVar(&opts.verbose, "l", false, "output byte numbers and differing byte values")
+	fs.Int64Var(&opts.limit, "n", -1, "compare at most `N` bytes")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		cli.Fprintf(stderr, "cmp", "missing operand")
+		return exitTrouble
+	}
+	if fs.NArg() > 2 {
+		cli.Fprintf(stderr, "cmp", "extra operand '%s'", fs.Arg(2))
+		return exitTrouble
+	}
+	name1, name2 := fs.Arg(0), "-"
+	if fs.NArg() == 2 {
+		name2 = fs.Arg(1)
+	}
+	if name1 == "-" && name2 == "-" {
+		// Both operands refer to the same stream, which is trivially identical.
+		return exitSame
+	}
+
+	r1, close1, err := open(name1, stdin)
+	if err != nil {
+		cli.Fprintf(stderr, "cmp", "%v", err)
+		return exitTrouble
+	}
+	defer close1()
+
+	r2, close2, err := open(name2, stdin)
+	if err != nil {
+		cli.Fprintf(stderr, "cmp", "%v", err)
+		return exitTrouble
+	}
+	defer close2()
+
+	return compare(name1, name2, r1, r2, &opts, stdout, stderr)
+}
+
+// open returns a reader for the named file, or stdin when the name is "-".
+// The returned function closes the underlying file, if any.
+func open(name string, stdin io.Reader) (io.Reader, func(), error) {
+	if name == "-" {
+		return stdin, func() {}, nil
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func() { file.Close() }, nil
+}
+
+// compare streams r1 and r2 side by side and reports their differences.
+// It returns the exit status cmp should terminate with.
+func compare(name1, name2 string, r1, r2 io.Reader, opts *options, stdout, stderr io.Writer) int {
+	b1 := bufio.NewReader(r1)
+	b2 := bufio.NewReader(r2)
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	var offset int64 // Number of bytes compared so far.
+	line := int64(1) // Current line number, counted in the first input.
+	status := exitSame
+
+	for opts.limit < 0 || offset < opts.limit {
+		c1, err1 := b1.ReadByte()
+		c2, err2 := b2.ReadByte()
+
+		// Surface any genuine read error before interpreting end of file.
+		if err := readError(name1, err1, name2, err2); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "cmp", "%v", err)
+			return exitTrouble
+		}
+
+		if err1 == io.EOF || err2 == io.EOF {
+			if err1 == io.EOF && err2 == io.EOF {
+				return status
+			}
+			// One input ended before the other: the shorter one is a prefix.
+			short := name1
+			if err2 == io.EOF {
+				short = name2
+			}
+			if !opts.silent {
+				out.Flush()
+				cli.Fprintf(stderr, "cmp", "EOF on %s after byte %d, line %d", short, offset, line)
+			}
+			return exitDiffer
+		}
+
+		offset++
+		if c1 != c2 {
+			status = exitDiffer
+			if opts.silent {
+				return status
+			}
+			if !opts.verbose {
+				fmt.Fprintf(out, "%s %s differ: byte %d, line %d\n", name1, name2, offset, line)
+				return status
+			}
+			// In verbose mode print the 1-based offset and both byte values in octal.
+			fmt.Fprintf(out, "%d %3o %3o\n", offset, c1, c2)
+		}
+		if c1 == '\n' {
+			line++
+		}
+	}
+	return status
+}
+
+// readError returns the first read error other than io.EOF, prefixed with
+// the name of the input it came from.
+func readError(name1 string, err1 error, name2 string, err2 error) error {
+	if err1 != nil && err1 != io.EOF {
+		return fmt.Errorf("%s: %w", name1, err1)
+	}
+	if err2 != nil && err2 != io.EOF {
+		return fmt.Errorf("%s: %w", name2, err2)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package cmp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates the named files with the given contents in a temp dir
+// and returns their paths in the same order.
+func writeFiles(t *testing.T, contents ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for i, content := range contents {
+		path := filepath.Join(dir, string(rune('a'+i)))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestRunIdentical(t *testing.T) {
+	paths := writeFiles(t, "hello\nworld\n", "hello\nworld\n")
+	var stdout, stderr bytes.Buffer
+
+	if code := run(paths, nil, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected exit code 0 but got %d", code)
+	}
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Errorf("Expected no output but got %q / %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestRunOneByteDifference(t *testing.T) {
+	paths := writeFiles(t, "hello\nworld\n", "hello\nwxrld\n")
+	var stdout, stderr bytes.Buffer
+
+	if code := run(paths, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	expected := paths[0] + " " + paths[1] + " differ: byte 8, line 2\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestRunDifferentLengths(t *testing.T) {
+	paths := writeFiles(t, "abc", "abcdef")
+	var stdout, stderr bytes.Buffer
+
+	if code := run(paths, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	expected := "cmp: EOF on " + paths[0] + " after byte 3, line 1\n"
+	if got := stderr.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestRunSilentListAndLimit(t *testing.T) {
+	paths := writeFiles(t, "abcd", "xbcy")
+
+	var stdout, stderr bytes.Buffer
+	if code := run(append([]string{"-s"}, paths...), nil, &stdout, &stderr); code != 1 || stdout.Len() != 0 {
+		t.Errorf("Expected silent exit 1 but got %d with %q", code, stdout.String())
+	}
+
+	stdout.Reset()
+	run(append([]string{"-l"}, paths...), nil, &stdout, &stderr)
+	expected := "1 141 170\n4 144 171\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	stdout.Reset()
+	if code := run(append([]string{"-n", "0"}, paths...), nil, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected exit code 0 with -n 0 but got %d", code)
+	}
+}
+
+func TestRunMissingFile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"/nonexistent/a", "/nonexistent/b"}, strings.NewReader(""), &stdout, &stderr)
+	if code != 2 {
+		t.Errorf("Expected exit code 2 but got %d", code)
+	}
+}
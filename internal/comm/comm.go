@@ -0,0 +1,167 @@
+// Package comm implements the functionality for the "comm" Unix tool.
+package comm
+
+import (
+	"bufio"   // Provides buffered reading and writing.
+	"flag"    // Used to parse command-line flags.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"strings" // For splitting combined flags and trimming newlines.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// input reads the lines of one of the files being compared, checking that
+// they come in sorted order.
+type input struct {
+	reader   *bufio.Reader
+	number   int    // 1 or 2, for diagnostics.
+	line     string // The current line, without its newline.
+	ok       bool   // Whether line holds a line, rather than the input having ended.
+	unsorted bool   // Whether a line was found out of order.
+}
+
+// Run is the entry point for the comm functionality.
+// It compares two sorted files line by line, printing the lines only in
+// the first, the lines only in the second and the lines in both in three
+// columns. It returns the exit status: 0 on success, 1 if a file could
+// not be read or was not sorted, or 2 for a usage error.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "comm".
+	fset := flag.NewFlagSet("comm", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-1" flag to hide lines only in the first file.
+	hide1 := fset.Bool("1", false, "suppress column 1 (lines unique to FILE1)")
+	// Define the "-2" flag to hide lines only in the second file.
+	hide2 := fset.Bool("2", false, "suppress column 2 (lines unique to FILE2)")
+	// Define the "-3" flag to hide lines in both files.
+	hide3 := fset.Bool("3", false, "suppress column 3 (lines that appear in both files)")
+	fset.Parse(splitColumns(args))
+	show := [3]bool{!*hide1, !*hide2, !*hide3}
+
+	switch {
+	case fset.NArg() < 2:
+		cli.Fprintf(stderr, "comm", "missing operand")
+		return cli.ExitUsage
+	case fset.NArg() > 2:
+		cli.Fprintf(stderr, "comm", "extra operand '%s'", fset.Arg(2))
+		return cli.ExitUsage
+	case fset.Arg(0) == "-" && fset.Arg(1) == "-":
+		cli.Fprintf(stderr, "comm", "only one file may be standard input")
+		return cli.ExitUsage
+	}
+
+	var inputs [2]*input
+	for i, name := range fset.Args() {
+		r := stdin
+		if name != "-" {
+			f, err := os.Open(name)
+			if err != nil {
+				cli.Fprintf(stderr, "comm", "%s: %v", name, cli.Unwrap(err))
+				return cli.ExitFailure
+			}
+			defer f.Close()
+			r = f
+		}
+		inputs[i] = &input{reader: bufio.NewReader(r), number: i + 1}
+	}
+
+	// Each column is indented by a tab for every column shown before it.
+	var prefix [3]string
+	for col := 1; col < 3; col++ {
+		prefix[col] = prefix[col-1]
+		if show[col-1] {
+			prefix[col] += "\t"
+		}
+	}
+
+	out := bufio.NewWriter(stdout)
+	in1, in2 := inputs[0], inputs[1]
+	var err error
+	if err = in1.next(out, stderr); err == nil {
+		err = in2.next(out, stderr)
+	}
+	for err == nil && (in1.ok || in2.ok) {
+		// Print whichever line sorts first, or both at once if they match.
+		col := 2
+		switch {
+		case !in2.ok || in1.ok && in1.line < in2.line:
+			col = 0
+		case !in1.ok || in2.line < in1.line:
+			col = 1
+		}
+		line := in1.line
+		if col == 1 {
+			line = in2.line
+		}
+		if show[col] {
+			out.WriteString(prefix[col] + line + "\n")
+		}
+		if col != 1 {
+			err = in1.next(out, stderr)
+		}
+		if err == nil && col != 0 {
+			err = in2.next(out, stderr)
+		}
+	}
+	if err != nil {
+		out.Flush()
+		cli.Fprintf(stderr, "comm", "%v", err)
+		return cli.ExitFailure
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "comm", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	if in1.unsorted || in2.unsorted {
+		cli.Fprintf(stderr, "comm", "input is not in sorted order")
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// splitColumns returns args with combined column flags such as "-12"
+// split into "-1" and "-2", which the flag parser does not accept as one.
+func splitColumns(args []string) []string {
+	var split []string
+	for i, arg := range args {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return append(split, args[i:]...)
+		}
+		if len(arg) > 2 && strings.Trim(arg[1:], "123") == "" {
+			for _, c := range arg[1:] {
+				split = append(split, "-"+string(c))
+			}
+			continue
+		}
+		split = append(split, arg)
+	}
+	return split
+}
+
+// next advances in to its next line, warning the first time a line sorts
+// before the one preceding it. Output buffered in w is flushed first, so
+// the warning appears where the problem was found.
+func (in *input) next(w *bufio.Writer, stderr io.Writer) error {
+	prev, hadPrev := in.line, in.ok
+	line, err := in.reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if line == "" {
+		in.line, in.ok = "", false
+		return nil
+	}
+	in.line, in.ok = strings.TrimSuffix(line, "\n"), true
+	if hadPrev && in.line < prev && !in.unsorted {
+		in.unsorted = true
+		w.Flush()
+		cli.Fprintf(stderr, "comm", "file %d is not in sorted order", in.number)
+	}
+	return nil
+}
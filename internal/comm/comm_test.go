@@ -0,0 +1,60 @@
+package comm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+func TestColumns(t *testing.T) {
+	file1 := testutil.TempFile(t, "file1", "apple\nbanana\ncherry\nfig\n")
+	file2 := testutil.TempFile(t, "file2", "banana\ndate\nfig\ngrape")
+	tests := []struct {
+		flags    []string
+		expected string
+	}{
+		{nil, "apple\n\t\tbanana\ncherry\n\tdate\n\t\tfig\n\tgrape\n"},
+		{[]string{"-1"}, "\tbanana\ndate\n\tfig\ngrape\n"},
+		{[]string{"-2"}, "apple\n\tbanana\ncherry\n\tfig\n"},
+		{[]string{"-3"}, "apple\ncherry\n\tdate\n\tgrape\n"},
+		{[]string{"-12"}, "banana\nfig\n"},
+		{[]string{"-1", "-3"}, "date\ngrape\n"},
+		{[]string{"-123"}, ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		args := append(tt.flags, file1, file2)
+		if status := run(args, strings.NewReader(""), &stdout, &stderr); status != 0 {
+			t.Fatalf("comm %v: Expected status 0 but got %d: %s", tt.flags, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("comm %v: Expected %q but got %q", tt.flags, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestStdin(t *testing.T) {
+	file2 := testutil.TempFile(t, "file2", "b\nc\n")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-", file2}, strings.NewReader("a\nb\n"), &stdout, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if expected := "a\n\t\tb\n\tc\n"; stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestUnsorted(t *testing.T) {
+	file1 := testutil.TempFile(t, "file1", "b\na\n")
+	file2 := testutil.TempFile(t, "file2", "a\n")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{file1, file2}, strings.NewReader(""), &stdout, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	expected := "comm: file 1 is not in sorted order\ncomm: input is not in sorted order\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
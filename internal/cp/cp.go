@@ -0,0 +1,247 @@
+// Package cp implements the functionality for the "cp" Unix tool.
+package cp
+
+import (
+	"bufio"         // Provides buffered reading and writing for copying data.
+	"errors"        // For recognizing existing directories.
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For formatting error messages.
+	"io"            // For copying data and the writer abstraction used for diagnostics.
+	"io/fs"         // For file modes and the existing-file error.
+	"os"            // For reading and creating files and directories.
+	"path/filepath" // For building target paths.
+	"strings"       // For recognizing copies of a directory into itself.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"      // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo" // For the access time of the source.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	recursive bool // -r, -R: copy directories and their contents.
+	preserve  bool // -p: also preserve modification and access times.
+}
+
+// Run is the entry point for the cp functionality.
+// It copies SOURCE to DEST, or each SOURCE into the directory DEST, and
+// returns the exit status: 0 on success, 1 if anything could not be
+// copied, or 2 on invalid usage.
+func Run(args []string) int {
+	return run(args, os.Stderr)
+}
+
+// run performs the actual work of Run, reporting errors to stderr.
+func run(args []string, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "cp".
+	fset := flag.NewFlagSet("cp", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-r" and "-R" flags to copy directories recursively.
+	fset.BoolVar(&opts.recursive, "r", false, "copy directories recursively")
+	fset.BoolVar(&opts.recursive, "R", false, "same as -r")
+	// Define the "-p" flag to preserve timestamps as well as modes.
+	fset.BoolVar(&opts.preserve, "p", false, "preserve timestamps as well as modes")
+	fset.Parse(args)
+
+	switch fset.NArg() {
+	case 0:
+		cli.Fprintf(stderr, "cp", "missing file operand")
+		return cli.ExitUsage
+	case 1:
+		cli.Fprintf(stderr, "cp", "missing destination file operand after '%s'", fset.Arg(0))
+		return cli.ExitUsage
+	}
+	sources := fset.Args()[:fset.NArg()-1]
+	dest := fset.Arg(fset.NArg() - 1)
+
+	// Copying into an existing directory keeps the sources' base names;
+	// several sources can only be copied that way.
+	destInfo, err := os.Stat(dest)
+	intoDir := err == nil && destInfo.IsDir()
+	if len(sources) > 1 && !intoDir {
+		cli.Fprintf(stderr, "cp", "target '%s' is not a directory", dest)
+		return cli.ExitFailure
+	}
+
+	status := cli.ExitSuccess
+	for _, src := range sources {
+		target := dest
+		if intoDir {
+			target = filepath.Join(dest, filepath.Base(src))
+		}
+		if err := copyPath(src, target, &opts); err != nil {
+			cli.Fprintf(stderr, "cp", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// CopyAll copies src to dst the way "cp -rp" would, except that a source
+// that is itself a symbolic link is copied as a link. It lets mv move
+// files between file systems, where they cannot simply be renamed.
+func CopyAll(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return fmt.Errorf("cannot stat '%s': %v", src, cli.Unwrap(err))
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return copySymlink(src, dst)
+	}
+	return copyPath(src, dst, &options{recursive: true, preserve: true})
+}
+
+// copyPath copies src to dst: a file's contents and mode, or with -r a
+// directory and everything in it. A source that is a symbolic link is
+// followed, but links inside a copied directory are copied as links.
+func copyPath(src, dst string, opts *options) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("cannot stat '%s': %v", src, cli.Unwrap(err))
+	}
+	if info.IsDir() {
+		if !opts.recursive {
+			return fmt.Errorf("-r not specified; omitting directory '%s'", src)
+		}
+		if Inside(dst, src) {
+			return fmt.Errorf("cannot copy a directory, '%s', into itself, '%s'", src, dst)
+		}
+		return copyDir(src, dst, info, opts)
+	}
+	// Like GNU cp, only -r recreates special files; without it their
+	// contents are read like those of a regular file.
+	if opts.recursive && !info.Mode().IsRegular() {
+		return copySpecial(src, dst, info, opts)
+	}
+	return copyFile(src, dst, info, opts)
+}
+
+// copyFile copies the contents of the regular file src to dst, creating
+// or truncating it, and gives dst the mode of src (and with -p its times).
+// Copying a file onto itself is refused, as truncating dst would destroy
+// the data.
+func copyFile(src, dst string, info fs.FileInfo, opts *options) error {
+	if dstInfo, err := os.Stat(dst); err == nil {
+		if os.SameFile(info, dstInfo) {
+			return fmt.Errorf("'%s' and '%s' are the same file", src, dst)
+		}
+		if dstInfo.IsDir() {
+			return fmt.Errorf("cannot overwrite directory '%s' with non-directory", dst)
+		}
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("cannot open '%s' for reading: %v", src, cli.Unwrap(err))
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("cannot create regular file '%s': %v", dst, cli.Unwrap(err))
+	}
+
+	w := bufio.NewWriter(out)
+	_, err = io.Copy(w, bufio.NewReader(in))
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error copying '%s' to '%s': %v", src, dst, cli.Unwrap(err))
+	}
+	return preserve(dst, info, opts)
+}
+
+// copyDir copies the directory src, described by info, and its contents
+// to dst, creating dst if it does not exist.
+func copyDir(src, dst string, info fs.FileInfo, opts *options) error {
+	// Keep the new directory writable until its contents are in place.
+	if err := os.Mkdir(dst, info.Mode().Perm()|0o700); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("cannot create directory '%s': %v", dst, cli.Unwrap(err))
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("cannot access '%s': %v", src, cli.Unwrap(err))
+	}
+
+	// Copy as much as possible, reporting the first failure.
+	var firstErr error
+	for _, entry := range entries {
+		from, to := filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())
+		var err error
+		if entry.Type()&fs.ModeSymlink != 0 {
+			err = copySymlink(from, to)
+		} else if childInfo, infoErr := entry.Info(); infoErr != nil {
+			err = fmt.Errorf("cannot stat '%s': %v", from, cli.Unwrap(infoErr))
+		} else {
+			switch entry.Type() {
+			case fs.ModeDir:
+				err = copyDir(from, to, childInfo, opts)
+			case 0:
+				err = copyFile(from, to, childInfo, opts)
+			default:
+				err = copySpecial(from, to, childInfo, opts)
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := preserve(dst, info, opts); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// copySpecial recreates the special file src, described by info, at dst.
+// Only FIFOs can be recreated; devices and sockets are reported instead
+// of being opened, as reading them could block or never end.
+func copySpecial(src, dst string, info fs.FileInfo, opts *options) error {
+	if info.Mode()&fs.ModeNamedPipe == 0 {
+		return fmt.Errorf("cannot copy special file '%s'", src)
+	}
+	if err := mkfifo(dst, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("cannot create fifo '%s': %v", dst, cli.Unwrap(err))
+	}
+	return preserve(dst, info, opts)
+}
+
+// copySymlink recreates the symbolic link src at dst, pointing at the
+// same target.
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return fmt.Errorf("cannot read symbolic link '%s': %v", src, cli.Unwrap(err))
+	}
+	if err := os.Symlink(target, dst); err != nil {
+		return fmt.Errorf("cannot create symbolic link '%s': %v", dst, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// preserve gives dst the mode of info, and with -p also its access and
+// modification times.
+func preserve(dst string, info fs.FileInfo, opts *options) error {
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("preserving permissions for '%s': %v", dst, cli.Unwrap(err))
+	}
+	if opts.preserve {
+		if err := os.Chtimes(dst, fileinfo.Atime(info), info.ModTime()); err != nil {
+			return fmt.Errorf("preserving times for '%s': %v", dst, cli.Unwrap(err))
+		}
+	}
+	return nil
+}
+
+// Inside reports whether path is dir itself or lies below it, which
+// rules out copying or moving dir to path.
+func Inside(path, dir string) bool {
+	absPath, err1 := filepath.Abs(path)
+	absDir, err2 := filepath.Abs(dir)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return absPath == absDir || strings.HasPrefix(absPath, absDir+string(filepath.Separator))
+}
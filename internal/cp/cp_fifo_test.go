@@ -0,0 +1,50 @@
+//go:build unix && !aix && !solaris
+
+package cp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// This test has a file of its own since syscall.Mkfifo is missing on AIX
+// and Solaris.
+func TestCopyRecursiveSpecialFiles(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	if err := os.Mkdir(src, 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := syscall.Mkfifo(filepath.Join(src, "pipe"), 0o640); err != nil {
+		t.Fatalf("Failed to create FIFO: %v", err)
+	}
+
+	// The FIFO is recreated rather than read, which would block.
+	var stderr bytes.Buffer
+	if status := run([]string{"-r", src, dst}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	info, err := os.Lstat(filepath.Join(dst, "pipe"))
+	if err != nil {
+		t.Fatalf("Failed to stat the copied FIFO: %v", err)
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 || info.Mode().Perm() != 0o640 {
+		t.Errorf("Expected a FIFO with mode 0640 but got %v", info.Mode())
+	}
+
+	// Devices are reported instead of being opened.
+	if _, err := os.Stat("/dev/null"); err != nil {
+		return
+	}
+	stderr.Reset()
+	if status := run([]string{"-r", "/dev/null", filepath.Join(dir, "null")}, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	if !strings.Contains(stderr.String(), "cannot copy special file '/dev/null'") {
+		t.Errorf("Expected a special file error but got %q", stderr.String())
+	}
+}
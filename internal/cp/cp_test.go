@@ -0,0 +1,135 @@
+package cp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo"
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+// checkFile fails the test unless path holds content.
+func checkFile(t *testing.T, path, content string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("Failed to read %s: %v", path, err)
+		return
+	}
+	if string(data) != content {
+		t.Errorf("Expected %q in %s but got %q", content, path, data)
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "src.sh"), filepath.Join(dir, "dst.sh")
+	testutil.WriteFile(t, src, "#!/bin/sh\n", 0o750)
+	old := time.Date(2001, 2, 3, 4, 5, 6, 0, time.Local)
+	accessed := time.Date(2002, 3, 4, 5, 6, 7, 0, time.Local)
+	os.Chtimes(src, accessed, old)
+
+	var stderr bytes.Buffer
+	if status := run([]string{src, dst}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, dst, "#!/bin/sh\n")
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", dst, err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o750 {
+		t.Errorf("Expected mode %o but got %o", 0o750, info.Mode().Perm())
+	}
+	if info.ModTime().Equal(old) {
+		t.Errorf("Expected a new modification time without -p")
+	}
+
+	// -p carries the access and modification times over.
+	os.Chtimes(src, accessed, old)
+	if status := run([]string{"-p", src, dst}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	info, _ = os.Stat(dst)
+	if !info.ModTime().Equal(old) {
+		t.Errorf("Expected %v but got %v", old, info.ModTime())
+	}
+	if runtime.GOOS != "windows" && !fileinfo.Atime(info).Equal(accessed) {
+		t.Errorf("Expected access time %v but got %v", accessed, fileinfo.Atime(info))
+	}
+}
+
+func TestCopyIntoDirectory(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	target := filepath.Join(dir, "target")
+	testutil.WriteFile(t, a, "A", 0o644)
+	testutil.WriteFile(t, b, "B", 0o644)
+	os.Mkdir(target, 0o755)
+
+	var stderr bytes.Buffer
+	if status := run([]string{a, b, target}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, filepath.Join(target, "a.txt"), "A")
+	checkFile(t, filepath.Join(target, "b.txt"), "B")
+
+	// Several sources need a directory to go into.
+	stderr.Reset()
+	status := run([]string{a, b, filepath.Join(dir, "nowhere")}, &stderr)
+	expected := "cp: target '" + filepath.Join(dir, "nowhere") + "' is not a directory\n"
+	if status != 1 || stderr.String() != expected {
+		t.Errorf("Expected %q (1) but got %q (%d)", expected, stderr.String(), status)
+	}
+}
+
+func TestCopyRecursive(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	testutil.WriteFile(t, filepath.Join(src, "top.txt"), "top", 0o644)
+	testutil.WriteFile(t, filepath.Join(src, "sub", "deep.txt"), "deep", 0o644)
+
+	var stderr bytes.Buffer
+	status := run([]string{src, filepath.Join(dir, "copy")}, &stderr)
+	expected := "cp: -r not specified; omitting directory '" + src + "'\n"
+	if status != 1 || stderr.String() != expected {
+		t.Errorf("Expected %q (1) but got %q (%d)", expected, stderr.String(), status)
+	}
+
+	stderr.Reset()
+	if status := run([]string{"-r", src, filepath.Join(dir, "copy")}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, filepath.Join(dir, "copy", "top.txt"), "top")
+	checkFile(t, filepath.Join(dir, "copy", "sub", "deep.txt"), "deep")
+
+	// Copying into an existing directory nests the source below it.
+	if status := run([]string{"-r", src, filepath.Join(dir, "copy")}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, filepath.Join(dir, "copy", "src", "sub", "deep.txt"), "deep")
+
+	// A directory cannot be copied into itself.
+	stderr.Reset()
+	run([]string{"-r", src, filepath.Join(src, "sub")}, &stderr)
+	expected = "cp: cannot copy a directory, '" + src + "', into itself, '" + filepath.Join(src, "sub", "src") + "'\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
+
+func TestSameFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.txt")
+	testutil.WriteFile(t, path, "precious", 0o644)
+	var stderr bytes.Buffer
+	status := run([]string{path, path}, &stderr)
+	expected := "cp: '" + path + "' and '" + path + "' are the same file\n"
+	if status != 1 || stderr.String() != expected {
+		t.Errorf("Expected %q (1) but got %q (%d)", expected, stderr.String(), status)
+	}
+	checkFile(t, path, "precious")
+}
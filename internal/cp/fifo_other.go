@@ -0,0 +1,13 @@
+//go:build !unix || aix || solaris
+
+package cp
+
+import (
+	"errors" // For the unsupported-operation error.
+	"io/fs"  // For the permission bits of the new FIFO.
+)
+
+// mkfifo reports that FIFOs cannot be created on this platform.
+func mkfifo(path string, perm fs.FileMode) error {
+	return &fs.PathError{Op: "mkfifo", Path: path, Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,16 @@
+//go:build unix && !aix && !solaris
+
+package cp
+
+import (
+	"io/fs"   // For the permission bits of the new FIFO.
+	"syscall" // For creating FIFOs.
+)
+
+// mkfifo creates a FIFO at path with the permissions perm, less the umask.
+func mkfifo(path string, perm fs.FileMode) error {
+	if err := syscall.Mkfifo(path, uint32(perm)); err != nil {
+		return &fs.PathError{Op: "mkfifo", Path: path, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Package cut implements the functionality for the "cut" Unix tool.
+package cut
+
+import (
+	"bufio"        // Provides buffered reading and writing.
+	"errors"       // For describing invalid lists.
+	"flag"         // Used to parse command-line flags.
+	"fmt"          // For formatting error messages.
+	"io"           // Provides the reader and writer abstractions used for input and output.
+	"os"           // For interacting with the file system and OS I/O.
+	"strconv"      // For parsing list positions.
+	"strings"      // For splitting lines into fields.
+	"unicode/utf8" // For validating the delimiter.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// span is a range of positions from lo to hi, counting from 1; a hi of 0
+// means the range extends to the end of the line.
+type span struct {
+	lo, hi int
+}
+
+// list is a set of selected positions.
+type list []span
+
+// contains reports whether position i is selected.
+func (l list) contains(i int) bool {
+	for _, s := range l {
+		if i >= s.lo && (s.hi == 0 || i <= s.hi) {
+			return true
+		}
+	}
+	return false
+}
+
+// options holds the parsed command-line flags.
+type options struct {
+	fields        list   // -f: the fields to print.
+	chars         list   // -c: the characters to print.
+	delim         string // -d: the field delimiter.
+	onlyDelimited bool   // -s: drop lines without the delimiter, with -f.
+}
+
+// Run is the entry point for the cut functionality.
+// It prints the selected fields or characters of every line of each file
+// (or stdin) and returns the exit status: 0 on success, 1 if any input
+// could not be read, or 2 on invalid options.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "cut".
+	fset := flag.NewFlagSet("cut", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-f" flag to select fields.
+	fields := fset.String("f", "", "select only these fields, such as `LIST` 1,3-5,7-")
+	// Define the "-c" flag to select characters.
+	chars := fset.String("c", "", "select only these characters, such as `LIST` 1,3-5,7-")
+	// Define the "-d" flag to set the field delimiter.
+	fset.StringVar(&opts.delim, "d", "\t", "use `DELIM` instead of TAB for field delimiter")
+	// Define the "-s" flag to drop lines without delimiters.
+	fset.BoolVar(&opts.onlyDelimited, "s", false, "do not print lines not containing delimiters")
+	fset.Parse(args)
+
+	var err error
+	switch {
+	case *fields != "" && *chars != "":
+		cli.Fprintf(stderr, "cut", "only one type of list may be specified")
+		return cli.ExitUsage
+	case *fields != "":
+		opts.fields, err = parseList(*fields)
+	case *chars != "":
+		opts.chars, err = parseList(*chars)
+	default:
+		cli.Fprintf(stderr, "cut", "you must specify a list of characters or fields")
+		return cli.ExitUsage
+	}
+	if err != nil {
+		cli.Fprintf(stderr, "cut", "%v", err)
+		return cli.ExitUsage
+	}
+	if utf8.RuneCountInString(opts.delim) != 1 {
+		cli.Fprintf(stderr, "cut", "the delimiter must be a single character")
+		return cli.ExitUsage
+	}
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for _, file := range files {
+		if err := cutFile(out, file, stdin, &opts); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "cut", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "cut", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// parseList parses a comma-separated list of positions and ranges, each
+// of the form N, N-M, N- or -M.
+func parseList(s string) (list, error) {
+	var l list
+	for _, item := range strings.Split(s, ",") {
+		lo, hi, isRange := strings.Cut(item, "-")
+		var sp span
+		var err error
+		switch {
+		case !isRange:
+			sp.lo, err = position(lo)
+			sp.hi = sp.lo
+		case lo == "" && hi == "":
+			return nil, errors.New("invalid range with no endpoint: -")
+		case lo == "":
+			sp.lo = 1
+			sp.hi, err = position(hi)
+		case hi == "":
+			sp.lo, err = position(lo)
+		default:
+			if sp.lo, err = position(lo); err == nil {
+				sp.hi, err = position(hi)
+			}
+			if err == nil && sp.hi < sp.lo {
+				return nil, errors.New("invalid decreasing range")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, sp)
+	}
+	return l, nil
+}
+
+// position parses a single position of a list.
+func position(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid field value '%s'", s)
+	}
+	if n < 1 {
+		return 0, errors.New("fields and positions are numbered from 1")
+	}
+	return n, nil
+}
+
+// cutFile cuts the lines of the named file, or of stdin for "-".
+func cutFile(w *bufio.Writer, file string, stdin io.Reader, opts *options) error {
+	if file == "-" {
+		return cut(w, stdin, opts)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	defer f.Close()
+	if err := cut(w, f, opts); err != nil {
+		return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// cut prints the selected part of each line of r, in input order.
+func cut(w *bufio.Writer, r io.Reader, opts *options) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimSuffix(line, "\n")
+			if opts.fields != nil {
+				cutFields(w, line, opts)
+			} else {
+				cutChars(w, line, opts)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// cutFields prints the selected fields of line, joined by the delimiter.
+// A line without the delimiter is printed whole, or dropped with -s.
+func cutFields(w *bufio.Writer, line string, opts *options) {
+	if !strings.Contains(line, opts.delim) {
+		if !opts.onlyDelimited {
+			w.WriteString(line + "\n")
+		}
+		return
+	}
+	first := true
+	for i, field := range strings.Split(line, opts.delim) {
+		if !opts.fields.contains(i + 1) {
+			continue
+		}
+		if !first {
+			w.WriteString(opts.delim)
+		}
+		w.WriteString(field)
+		first = false
+	}
+	w.WriteString("\n")
+}
+
+// cutChars prints the selected characters of line.
+func cutChars(w *bufio.Writer, line string, opts *options) {
+	i := 0
+	for _, c := range line {
+		i++
+		if opts.chars.contains(i) {
+			w.WriteRune(c)
+		}
+	}
+	w.WriteString("\n")
+}
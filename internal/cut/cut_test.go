@@ -0,0 +1,87 @@
+package cut
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// runCut runs cut with args over input and returns its output, diagnostics
+// and status.
+func runCut(input string, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(input), &stdout, &stderr)
+	return stdout.String(), stderr.String(), status
+}
+
+func TestFields(t *testing.T) {
+	input := "root:x:0:0:root:/root:/bin/sh\nno delimiter here\nuser:x:1000"
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-d", ":", "-f", "1"}, "root\nno delimiter here\nuser\n"},
+		{[]string{"-d", ":", "-f", "1,3-4"}, "root:0:0\nno delimiter here\nuser:1000\n"},
+		{[]string{"-d", ":", "-f", "6-"}, "/root:/bin/sh\nno delimiter here\n\n"},
+		{[]string{"-d", ":", "-f", "-2"}, "root:x\nno delimiter here\nuser:x\n"},
+		{[]string{"-d", ":", "-f", "3,1"}, "root:0\nno delimiter here\nuser:1000\n"},
+		{[]string{"-f", "2"}, "root:x:0:0:root:/root:/bin/sh\nno delimiter here\nuser:x:1000\n"},
+	}
+	for _, tt := range tests {
+		got, stderr, status := runCut(input, tt.args...)
+		if got != tt.expected || status != 0 {
+			t.Errorf("cut %q: Expected %q (0) but got %q (%d) %s", tt.args, tt.expected, got, status, stderr)
+		}
+	}
+
+	got, _, _ := runCut("a\tb\tc\n", "-f", "2")
+	if got != "b\n" {
+		t.Errorf("Expected tab to be the default delimiter, got %q", got)
+	}
+}
+
+func TestSuppress(t *testing.T) {
+	got, _, _ := runCut("a,b\nplain\nc,d\n", "-s", "-d", ",", "-f", "2")
+	if expected := "b\nd\n"; got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestCharacters(t *testing.T) {
+	tests := []struct {
+		list     string
+		expected string
+	}{
+		{"1", "h\n"},
+		{"1,3-5,7-", "hllowörld\n"},
+		{"-3", "hel\n"},
+		{"8", "ö\n"},
+		{"20-", "\n"},
+	}
+	for _, tt := range tests {
+		got, stderr, status := runCut("hello wörld\n", "-c", tt.list)
+		if got != tt.expected || status != 0 {
+			t.Errorf("cut -c %s: Expected %q (0) but got %q (%d) %s", tt.list, tt.expected, got, status, stderr)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stderr string
+	}{
+		{nil, "cut: you must specify a list of characters or fields\n"},
+		{[]string{"-f", "0"}, "cut: fields and positions are numbered from 1\n"},
+		{[]string{"-f", "3-1"}, "cut: invalid decreasing range\n"},
+		{[]string{"-f", "x"}, "cut: invalid field value 'x'\n"},
+		{[]string{"-f", "1", "-d", "ab"}, "cut: the delimiter must be a single character\n"},
+		{[]string{"-f", "1", "-c", "1"}, "cut: only one type of list may be specified\n"},
+	}
+	for _, tt := range tests {
+		_, stderr, status := runCut("", tt.args...)
+		if stderr != tt.stderr || status != 2 {
+			t.Errorf("cut %q: Expected %q (2) but got %q (%d)", tt.args, tt.stderr, stderr, status)
+		}
+	}
+}
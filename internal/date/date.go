@@ -0,0 +1,155 @@
+// Package date implements the functionality for the "date" Unix tool.
+package date
+
+import (
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting numeric directives.
+	"io"      // Provides the writer abstraction used for output.
+	"os"      // For the standard streams.
+	"strings" // For building the formatted date.
+	"time"    // For reading and formatting the time.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// defaultFormat is how the date is printed when no +FORMAT is given.
+const defaultFormat = "%a %b %e %H:%M:%S %Z %Y"
+
+// Run is the entry point for the date functionality.
+// It prints the current time, or the one given with -d, in the default
+// format or as described by +FORMAT, and returns the exit status: 0 on
+// success, 1 if the date could not be printed, or 2 for a usage error.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr, time.Now)
+}
+
+// run performs the actual work of Run using the provided streams and
+// taking the current time from now.
+func run(args []string, stdout, stderr io.Writer, now func() time.Time) int {
+	// Create a new FlagSet for parsing command-line options specific to "date".
+	fset := flag.NewFlagSet("date", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-u" flag to print Coordinated Universal Time.
+	utc := fset.Bool("u", false, "print Coordinated Universal Time (UTC)")
+	// Define the "-d" flag to print another time than now.
+	dateArg := fset.String("d", "", "display time described by `STRING`, not 'now'")
+	fset.Parse(args)
+
+	format := defaultFormat
+	switch {
+	case fset.NArg() > 1:
+		cli.Fprintf(stderr, "date", "extra operand '%s'", fset.Arg(1))
+		return cli.ExitUsage
+	case fset.NArg() == 1 && !strings.HasPrefix(fset.Arg(0), "+"):
+		cli.Fprintf(stderr, "date", "invalid format '%s': it must start with '+'; setting the date is not supported", fset.Arg(0))
+		return cli.ExitUsage
+	case fset.NArg() == 1:
+		format = fset.Arg(0)[1:]
+	}
+
+	loc := time.Local
+	if *utc {
+		loc = time.UTC
+	}
+	t := now().In(loc)
+	if *dateArg != "" {
+		var err error
+		if t, err = Parse(*dateArg, t, loc); err != nil {
+			cli.Fprintf(stderr, "date", "invalid date '%s'", *dateArg)
+			return cli.ExitFailure
+		}
+	}
+
+	if _, err := io.WriteString(stdout, strftime(format, t)+"\n"); err != nil {
+		cli.Fprintf(stderr, "date", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// directiveLayouts maps the strftime directives that Go's reference-time
+// layouts can express to those layouts.
+var directiveLayouts = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'e': "_2",
+	'H': "15",
+	'I': "03",
+	'M': "04",
+	'S': "05",
+	'p': "PM",
+	'z': "-0700",
+	'Z': "MST",
+	'A': "Monday",
+	'a': "Mon",
+	'B': "January",
+	'b': "Jan",
+	'h': "Jan",
+	'j': "002",
+}
+
+// composites maps the directives that stand for several others to their
+// expansion.
+var composites = map[byte]string{
+	'c': "%a %b %e %H:%M:%S %Y",
+	'D': "%m/%d/%y",
+	'F': "%Y-%m-%d",
+	'R': "%H:%M",
+	'T': "%H:%M:%S",
+	'r': "%I:%M:%S %p",
+	'x': "%m/%d/%y",
+	'X': "%H:%M:%S",
+}
+
+// strftime formats t as described by format, in which each %-directive
+// is replaced by part of the time, e.g. "%Y-%m-%d" gives "2006-01-02".
+// Unknown directives are printed as they are.
+func strftime(format string, t time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i == len(format)-1 {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		c := format[i]
+		if layout, ok := directiveLayouts[c]; ok {
+			b.WriteString(t.Format(layout))
+			continue
+		}
+		if expansion, ok := composites[c]; ok {
+			b.WriteString(strftime(expansion, t))
+			continue
+		}
+		switch c {
+		case '%':
+			b.WriteByte('%')
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'P':
+			b.WriteString(strings.ToLower(t.Format("PM")))
+		case 'k':
+			fmt.Fprintf(&b, "%2d", t.Hour())
+		case 'l':
+			fmt.Fprintf(&b, "%2d", (t.Hour()+11)%12+1)
+		case 'C':
+			fmt.Fprintf(&b, "%02d", t.Year()/100)
+		case 'u':
+			fmt.Fprintf(&b, "%d", (int(t.Weekday())+6)%7+1)
+		case 'w':
+			fmt.Fprintf(&b, "%d", int(t.Weekday()))
+		case 's':
+			fmt.Fprintf(&b, "%d", t.Unix())
+		case 'N':
+			fmt.Fprintf(&b, "%09d", t.Nanosecond())
+		default:
+			b.WriteByte('%')
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package date
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// fixedNow returns a clock that always reads 2024-03-05 07:08:09.000123456
+// in a zone five hours east of UTC.
+func fixedNow() time.Time {
+	zone := time.FixedZone("TST", 5*60*60)
+	return time.Date(2024, time.March, 5, 7, 8, 9, 123456, zone)
+}
+
+func TestStrftime(t *testing.T) {
+	now := fixedNow()
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2024-03-05 07:08:09"},
+		{"%y %e %j", "24  5 065"},
+		{"%A %a %B %b", "Tuesday Tue March Mar"},
+		{"%I:%M %p %P %l", "07:08 AM am  7"},
+		{"%z %Z", "+0500 TST"},
+		{"%F %T", "2024-03-05 07:08:09"},
+		{"%D %R", "03/05/24 07:08"},
+		{"%u %w %C", "2 2 20"},
+		{"%s.%N", "1709604489.000123456"},
+		{"100%% %q %", "100% %q %"},
+	}
+	for _, tt := range tests {
+		if got := strftime(tt.format, now); got != tt.expected {
+			t.Errorf("strftime(%q): Expected %q but got %q", tt.format, tt.expected, got)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-u"}, "Tue Mar  5 02:08:09 UTC 2024\n"},
+		{[]string{"-u", "+%H:%M %Z"}, "02:08 UTC\n"},
+		{[]string{"-u", "-d", "@0", "+%F %T"}, "1970-01-01 00:00:00\n"},
+		{[]string{"-u", "-d", "2020-02-29 12:30", "+%A %j"}, "Saturday 060\n"},
+		{[]string{"-u", "-d", "tomorrow", "+%F"}, "2024-03-06\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, &stdout, &stderr, fixedNow); status != 0 {
+			t.Fatalf("date %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("date %v: Expected %q but got %q", tt.args, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	tests := []struct {
+		args   []string
+		status int
+		stderr string
+	}{
+		{[]string{"-d", "someday"}, 1, "date: invalid date 'someday'\n"},
+		{[]string{"+%Y", "+%m"}, 2, "date: extra operand '+%m'\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, &stdout, &stderr, fixedNow); status != tt.status {
+			t.Errorf("date %v: Expected status %d but got %d", tt.args, tt.status, status)
+		}
+		if stderr.String() != tt.stderr {
+			t.Errorf("date %v: Expected %q but got %q", tt.args, tt.stderr, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package date
+
+import (
+	"strconv" // For parsing "@SECONDS" dates.
+	"strings" // For recognizing relative dates.
+	"time"    // For parsing and representing times.
+)
+
+// parseLayouts are the absolute forms of date accepted by Parse, tried
+// in order.
+var parseLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Parse parses a date given to -d: "@SECONDS" since the Unix epoch, one
+// of the relative words now, today, yesterday and tomorrow (counted from
+// now), or an absolute date in one of parseLayouts. Absolute dates are in
+// loc unless they name their own zone.
+func Parse(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	if secs, ok := strings.CutPrefix(s, "@"); ok {
+		n, err := strconv.ParseInt(secs, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(n, 0).In(loc), nil
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "now", "today":
+		return now.In(loc), nil
+	case "yesterday":
+		return now.In(loc).AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return now.In(loc).AddDate(0, 0, 1), nil
+	}
+	var err error
+	for _, layout := range parseLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,188 @@
+// Package df implements the functionality for the "df" Unix tool.
+package df
+
+import (
+	"errors"        // For recognizing unsupported platforms.
+	"flag"          // Used to parse command-line flags.
+	"io"            // Provides the writer abstraction used for output.
+	"os"            // For checking the operands exist.
+	"path/filepath" // For matching operands to mount points.
+	"strconv"       // For formatting the figures.
+	"strings"       // For padding the columns.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"      // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo" // For human-readable sizes.
+)
+
+// mount is an entry of the mount table.
+type mount struct {
+	source string // The device or other source mounted, e.g. "/dev/sda1".
+	target string // Where it is mounted, e.g. "/".
+}
+
+// space describes the capacity of a filesystem.
+type space struct {
+	size   uint64 // Total bytes.
+	free   uint64 // Bytes not in use, including those reserved for root.
+	avail  uint64 // Bytes available to unprivileged users.
+	inodes uint64 // Total inodes.
+	ifree  uint64 // Inodes not in use.
+}
+
+// Run is the entry point for the df functionality.
+// It prints the size, used and available space of the filesystems holding
+// the given files, or of every mounted filesystem, and returns the exit
+// status: 0 on success, or 1 if any filesystem could not be examined.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "df".
+	fset := flag.NewFlagSet("df", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-h" flag to print sizes in human-readable units.
+	human := fset.Bool("h", false, "print sizes in human readable format (e.g., 1K 234M 2G)")
+	// Define the "-i" flag to report inodes instead of blocks.
+	inodes := fset.Bool("i", false, "list inode information instead of block usage")
+	fset.Parse(args)
+
+	table, err := mounts()
+	if err != nil {
+		cli.Fprintf(stderr, "df", "cannot read table of mounted file systems: %v", err)
+		return cli.ExitFailure
+	}
+
+	status := cli.ExitSuccess
+	var selected []mount
+	if fset.NArg() == 0 {
+		selected = table
+	} else {
+		for _, path := range fset.Args() {
+			if _, err := os.Stat(path); err != nil {
+				cli.Fprintf(stderr, "df", "cannot access '%s': %v", path, cli.Unwrap(err))
+				status = cli.ExitFailure
+				continue
+			}
+			selected = append(selected, mountOf(table, path))
+		}
+	}
+
+	rows := [][]string{header(*human, *inodes)}
+	for _, m := range selected {
+		sp, err := statfs(m.target)
+		if err != nil {
+			cli.Fprintf(stderr, "df", "%s: %v", m.target, cli.Unwrap(err))
+			status = cli.ExitFailure
+			if errors.Is(err, errors.ErrUnsupported) {
+				break
+			}
+			continue
+		}
+		// Without operands, pseudo filesystems such as proc, which have no
+		// size, are left out.
+		if fset.NArg() == 0 && sp.size == 0 && (!*inodes || sp.inodes == 0) {
+			continue
+		}
+		rows = append(rows, row(m, sp, *human, *inodes))
+	}
+	if len(rows) > 1 {
+		if err := writeTable(stdout, rows); err != nil {
+			cli.Fprintf(stderr, "df", "write error: %v", err)
+			return cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// header returns the column titles for the chosen kind of report.
+func header(human, inodes bool) []string {
+	switch {
+	case inodes:
+		return []string{"Filesystem", "Inodes", "IUsed", "IFree", "IUse%", "Mounted on"}
+	case human:
+		return []string{"Filesystem", "Size", "Used", "Avail", "Use%", "Mounted on"}
+	default:
+		return []string{"Filesystem", "1K-blocks", "Used", "Available", "Use%", "Mounted on"}
+	}
+}
+
+// row returns the columns describing the filesystem mounted by m.
+func row(m mount, sp space, human, inodes bool) []string {
+	if inodes {
+		used := sp.inodes - sp.ifree
+		return []string{m.source, count(sp.inodes), count(used), count(sp.ifree), percent(used, sp.ifree), m.target}
+	}
+	used := sp.size - sp.free
+	format := func(n uint64) string {
+		if human {
+			return fileinfo.HumanSize(int64(n))
+		}
+		// Sizes are shown in kilobytes, rounded up.
+		return count((n + 1023) / 1024)
+	}
+	return []string{m.source, format(sp.size), format(used), format(sp.avail), percent(used, sp.avail), m.target}
+}
+
+// count formats n in decimal.
+func count(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
+
+// percent returns how much of the space an unprivileged user could have
+// is in use, rounded up, or "-" if there is no such space.
+func percent(used, avail uint64) string {
+	total := used + avail
+	if total == 0 {
+		return "-"
+	}
+	return strconv.FormatUint((used*100+total-1)/total, 10) + "%"
+}
+
+// writeTable prints rows in aligned columns: the first is left-aligned,
+// the figures are right-aligned, and the last is printed as it is.
+func writeTable(w io.Writer, rows [][]string) error {
+	widths := make([]int, len(rows[0]))
+	for _, r := range rows {
+		for i, col := range r {
+			widths[i] = max(widths[i], len(col))
+		}
+	}
+	var b strings.Builder
+	for _, r := range rows {
+		b.WriteString(r[0] + strings.Repeat(" ", widths[0]-len(r[0])))
+		for i := 1; i < len(r)-1; i++ {
+			b.WriteString(" " + strings.Repeat(" ", widths[i]-len(r[i])) + r[i])
+		}
+		b.WriteString(" " + r[len(r)-1] + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mountOf returns the entry of table for the filesystem holding path: the
+// one mounted on the longest directory that contains it. Paths outside
+// every known mount are reported as their own mount point.
+func mountOf(table []mount, path string) mount {
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		// Resolve symbolic links, which may lead to another filesystem.
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+	}
+	best := mount{source: "-", target: path}
+	bestLen := -1
+	for _, m := range table {
+		rel, err := filepath.Rel(m.target, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		// Later entries mounted on the same directory hide earlier ones.
+		if len(m.target) >= bestLen {
+			best, bestLen = m, len(m.target)
+		}
+	}
+	return best
+}
@@ -0,0 +1,72 @@
+package df
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStatfs(t *testing.T) {
+	sp, err := statfs(".")
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Filesystem statistics are not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("Failed to query the filesystem of .: %v", err)
+	}
+	if sp.size == 0 {
+		t.Errorf("Expected a non-zero size but got %+v", sp)
+	}
+	if sp.free > sp.size || sp.avail > sp.size {
+		t.Errorf("Expected free and available space within the size but got %+v", sp)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := statfs("."); err != nil {
+		t.Skipf("Cannot query the filesystem of .: %v", err)
+	}
+	tests := []struct {
+		args   []string
+		header string
+	}{
+		{[]string{"."}, "Filesystem 1K-blocks Used Available Use% Mounted on"},
+		{[]string{"-h", "."}, "Filesystem Size Used Avail Use% Mounted on"},
+		{[]string{"-i", "."}, "Filesystem Inodes IUsed IFree IUse% Mounted on"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, &stdout, &stderr); status != 0 {
+			t.Fatalf("df %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("df %v: Expected a header and one row but got %q", tt.args, stdout.String())
+		}
+		if header := strings.Join(strings.Fields(lines[0]), " "); header != tt.header {
+			t.Errorf("df %v: Expected %q but got %q", tt.args, tt.header, header)
+		}
+		if fields := strings.Fields(lines[1]); len(fields) < 6 || fields[1] == "0" {
+			t.Errorf("df %v: Expected a non-zero total but got %q", tt.args, lines[1])
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		used, avail uint64
+		expected    string
+	}{
+		{0, 0, "-"},
+		{0, 100, "0%"},
+		{1, 199, "1%"},
+		{50, 50, "50%"},
+		{100, 0, "100%"},
+	}
+	for _, tt := range tests {
+		if got := percent(tt.used, tt.avail); got != tt.expected {
+			t.Errorf("percent(%d, %d): Expected %q but got %q", tt.used, tt.avail, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package df
+
+import (
+	"bufio"   // For reading the mount table line by line.
+	"os"      // For opening the mount table.
+	"strconv" // For decoding octal escapes.
+	"strings" // For splitting the fields of each entry.
+)
+
+// mountTable is where the kernel lists the filesystems mounted in the
+// current process's namespace.
+const mountTable = "/proc/self/mounts"
+
+// mounts returns the entries of the mount table, in the order they were
+// mounted.
+func mounts() ([]mount, error) {
+	f, err := os.Open(mountTable)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var table []mount
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		table = append(table, mount{source: unescape(fields[0]), target: unescape(fields[1])})
+	}
+	return table, scanner.Err()
+}
+
+// unescape decodes the octal escapes, such as "\040" for a space, that
+// the mount table uses for blanks and backslashes in names.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
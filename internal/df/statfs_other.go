@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || dragonfly)
+
+package df
+
+import "errors" // For the error reported on unsupported platforms.
+
+// statfs reports that filesystem capacity cannot be queried on this
+// platform.
+func statfs(path string) (space, error) {
+	return space{}, errors.ErrUnsupported
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd || dragonfly
+
+package df
+
+import "syscall" // For the statfs system call.
+
+// statfs returns the capacity of the filesystem holding path.
+func statfs(path string) (space, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return space{}, err
+	}
+	// The field types differ between platforms, so each is converted.
+	bsize := uint64(st.Bsize)
+	return space{
+		size:   uint64(st.Blocks) * bsize,
+		free:   uint64(st.Bfree) * bsize,
+		avail:  uint64(st.Bavail) * bsize,
+		inodes: uint64(st.Files),
+		ifree:  uint64(st.Ffree),
+	}, nil
+}
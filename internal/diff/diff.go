@@ -0,0 +1,382 @@
+// Package diff implements the functionality for the "diff" Unix tool.
+package diff
+
+import (
+	"bufio"   // Provides buffered reading of input lines and buffered output.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatted I/O operations.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"strings" // Provides functions for string manipulation.
+	"time"    // For the modification times shown in file headers.
+	"unicode" // For classifying whitespace when it is ignored.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Exit statuses reported by diff, matching coreutils.
+const (
+	exitSame    = 0 // The inputs are identical.
+	exitDiffer  = 1 // The inputs differ.
+	exitTrouble = 2 // An error prevented the comparison.
+)
+
+// Timestamp layouts used in file headers, matching GNU diff.
+const (
+	unifiedTimeFormat = "2006-01-02 15:04:05.000000000 -0700"
+	contextTimeFormat = "Mon Jan _2 15:04:05 2006"
+)
+
+// outputFormat selects how differences are printed.
+type outputFormat int
+
+const (
+	formatNormal  outputFormat = iota // Classic "2c2" style output.
+	formatUnified                     // Unified output as produced by -u.
+	formatContext                     // Context output as produced by -c.
+)
+
+// options holds the parsed command-line flags for diff.
+type options struct {
+	format      outputFormat // Output style.
+	context     int          // Lines of context around each change.
+	brief       bool         // -q: only report whether the files differ.
+	ignoreCase  bool         // -i: compare lines case-insensitively.
+	ignoreSpace bool         // -w: ignore all white space when comparing.
+}
+
+// file holds the lines of one input along with the metadata shown in headers.
+type file struct {
+	name    string    // Name as given on the command line.
+	modTime time.Time // Modification time, or the current time for stdin.
+	lines   []string  // Lines without their trailing newline.
+	noEOL   bool      // True if the last line is not terminated by a newline.
+}
+
+// Run is the entry point for the diff functionality.
+// It returns 0 if the inputs are the same, 1 if they differ, and 2 on error.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "diff".
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	unified := fs.Bool("u", false, "output 3 lines of unified context")
+	unifiedLines := fs.Int("U", -1, "output `NUM` lines of unified context")
+	contextFmt := fs.Bool("c", false, "output 3 lines of copied context")
+	contextLines := fs.Int("C", -1, "output `NUM` lines of copied context")
+	var opts options
+	fs.BoolVar(&opts.brief, "q", false, "report only when files differ")
+	fs.BoolVar(&opts.ignoreCase, "i", false, "ignore case differences in file contents")
+	fs.BoolVar(&opts.ignoreSpace, "w", false, "ignore all white space")
+	fs.Parse(args)
+
+	// Work out the output format and amount of context from the flags.
+	opts.context = 3
+	switch {
+	case *unified || *unifiedLines >= 0:
+		opts.format = formatUnified
+		if *unifiedLines >= 0 {
+			opts.context = *unifiedLines
+		}
+	case *contextFmt || *contextLines >= 0:
+		opts.format = formatContext
+		if *contextLines >= 0 {
+			opts.context = *contextLines
+		}
+	}
+
+	if fs.NArg() != 2 {
+		if fs.NArg() < 2 {
+			cli.Fprintf(stderr, "diff", "missing operand after '%s'", strings.Join(fs.Args(), " "))
+		} else {
+			cli.Fprintf(stderr, "diff", "extra operand '%s'", fs.Arg(2))
+		}
+		return exitTrouble
+	}
+
+	a, err := readFile(fs.Arg(0), stdin)
+	if err != nil {
+		cli.Fprintf(stderr, "diff", "%v", err)
+		return exitTrouble
+	}
+	b, err := readFile(fs.Arg(1), stdin)
+	if err != nil {
+		cli.Fprintf(stderr, "diff", "%v", err)
+		return exitTrouble
+	}
+
+	// Compare normalized keys so -i and -w only affect matching, not output.
+	changes := computeChanges(compareKeys(a, &opts), compareKeys(b, &opts))
+	if len(changes) == 0 {
+		return exitSame
+	}
+
+	if opts.brief {
+		fmt.Fprintf(stdout, "Files %s and %s differ\n", a.name, b.name)
+		return exitDiffer
+	}
+
+	w := bufio.NewWriter(stdout)
+	switch opts.format {
+	case formatUnified:
+		writeUnified(w, a, b, changes, opts.context)
+	case formatContext:
+		writeContext(w, a, b, changes, opts.context)
+	default:
+		writeNormal(w, a, b, changes)
+	}
+	if err := w.Flush(); err != nil {
+		cli.Fprintf(stderr, "diff", "write error: %v", err)
+		return exitTrouble
+	}
+	return exitDiffer
+}
+
+// readFile loads the named file, or stdin when the name is "-", into lines.
+func readFile(name string, stdin io.Reader) (*file, error) {
+	f := &file{name: name, modTime: time.Now()}
+
+	var r io.Reader = stdin
+	if name != "-" {
+		fh, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer fh.Close()
+
+		info, err := fh.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s: Is a directory", name)
+		}
+		f.modTime = info.ModTime()
+		r = fh
+	}
+
+	// Read line by line, keeping track of a missing final newline.
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if strings.HasSuffix(line, "\n") {
+				line = line[:len(line)-1]
+			} else {
+				f.noEOL = true
+			}
+			f.lines = append(f.lines, line)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return f, nil
+}
+
+// compareKeys returns the strings used to decide whether two lines match.
+func compareKeys(f *file, opts *options) []string {
+	keys := make([]string, len(f.lines))
+	for i, line := range f.lines {
+		if opts.ignoreCase {
+			line = strings.ToLower(line)
+		}
+		if opts.ignoreSpace {
+			line = strings.Map(func(r rune) rune {
+				if unicode.IsSpace(r) {
+					return -1 // Drop white space entirely.
+				}
+				return r
+			}, line)
+		} else if f.noEOL && i == len(f.lines)-1 {
+			// A final line without a newline differs from one with a newline.
+			line += "\x00"
+		}
+		keys[i] = line
+	}
+	return keys
+}
+
+// hunk is a group of nearby changes printed together with shared context.
+type hunk struct {
+	a0, a1  int      // Range of lines of the first input covered by the hunk.
+	b0, b1  int      // Range of lines of the second input covered by the hunk.
+	changes []change // Changes contained in the hunk.
+}
+
+// groupHunks merges changes whose surrounding context would overlap.
+func groupHunks(changes []change, context, lenA, lenB int) []hunk {
+	var hunks []hunk
+	for _, c := range changes {
+		if n := len(hunks); n > 0 && c.a0-hunks[n-1].changes[len(hunks[n-1].changes)-1].a1 <= 2*context {
+			hunks[n-1].changes = append(hunks[n-1].changes, c)
+			continue
+		}
+		hunks = append(hunks, hunk{changes: []change{c}})
+	}
+
+	// Extend every hunk by the requested context on both sides.
+	for i := range hunks {
+		first := hunks[i].changes[0]
+		last := hunks[i].changes[len(hunks[i].changes)-1]
+		before := min(context, first.a0, first.b0)
+		after := min(context, lenA-last.a1, lenB-last.b1)
+		hunks[i].a0, hunks[i].b0 = first.a0-before, first.b0-before
+		hunks[i].a1, hunks[i].b1 = last.a1+after, last.b1+after
+	}
+	return hunks
+}
+
+// writeLine prints a single line with the given prefix, followed by the
+// "No newline" marker if it is the unterminated last line of f.
+func writeLine(w io.Writer, prefix string, f *file, i int) {
+	fmt.Fprintf(w, "%s%s\n", prefix, f.lines[i])
+	if f.noEOL && i == len(f.lines)-1 {
+		fmt.Fprintln(w, `\ No newline at end of file`)
+	}
+}
+
+// writeUnified prints the changes in unified format.
+func writeUnified(w io.Writer, a, b *file, changes []change, context int) {
+	fmt.Fprintf(w, "--- %s\t%s\n", a.name, a.modTime.Format(unifiedTimeFormat))
+	fmt.Fprintf(w, "+++ %s\t%s\n", b.name, b.modTime.Format(unifiedTimeFormat))
+
+	for _, h := range groupHunks(changes, context, len(a.lines), len(b.lines)) {
+		fmt.Fprintf(w, "@@ -%s +%s @@\n", unifiedRange(h.a0, h.a1), unifiedRange(h.b0, h.b1))
+		pos := h.a0
+		for _, c := range h.changes {
+			for ; pos < c.a0; pos++ {
+				writeLine(w, " ", a, pos)
+			}
+			for i := c.a0; i < c.a1; i++ {
+				writeLine(w, "-", a, i)
+			}
+			for i := c.b0; i < c.b1; i++ {
+				writeLine(w, "+", b, i)
+			}
+			pos = c.a1
+		}
+		for ; pos < h.a1; pos++ {
+			writeLine(w, " ", a, pos)
+		}
+	}
+}
+
+// unifiedRange formats the half-open line range [start, end) for a unified
+// hunk header. Empty ranges name the line before them, as GNU diff does.
+func unifiedRange(start, end int) string {
+	switch count := end - start; count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// writeContext prints the changes in context format.
+func writeContext(w io.Writer, a, b *file, changes []change, context int) {
+	fmt.Fprintf(w, "*** %s\t%s\n", a.name, a.modTime.Format(contextTimeFormat))
+	fmt.Fprintf(w, "--- %s\t%s\n", b.name, b.modTime.Format(contextTimeFormat))
+
+	for _, h := range groupHunks(changes, context, len(a.lines), len(b.lines)) {
+		fmt.Fprintln(w, "***************")
+
+		// Each side is only printed when it contains changed lines.
+		deletes, inserts := false, false
+		for _, c := range h.changes {
+			deletes = deletes || c.a1 > c.a0
+			inserts = inserts || c.b1 > c.b0
+		}
+
+		fmt.Fprintf(w, "*** %s ****\n", contextRange(h.a0, h.a1))
+		if deletes {
+			writeContextSide(w, a, h.a0, h.a1, h.changes, func(c change) (int, int, bool) {
+				return c.a0, c.a1, c.b1 > c.b0
+			}, "- ")
+		}
+		fmt.Fprintf(w, "--- %s ----\n", contextRange(h.b0, h.b1))
+		if inserts {
+			writeContextSide(w, b, h.b0, h.b1, h.changes, func(c change) (int, int, bool) {
+				return c.b0, c.b1, c.a1 > c.a0
+			}, "+ ")
+		}
+	}
+}
+
+// writeContextSide prints one side of a context hunk. span reports the
+// range a change covers on this side and whether the other side also has
+// lines, in which case the lines are marked as changed rather than added
+// or removed.
+func writeContextSide(w io.Writer, f *file, start, end int, changes []change, span func(change) (int, int, bool), marker string) {
+	pos := start
+	for _, c := range changes {
+		lo, hi, both := span(c)
+		for ; pos < lo; pos++ {
+			writeLine(w, "  ", f, pos)
+		}
+		prefix := marker
+		if both {
+			prefix = "! "
+		}
+		for ; pos < hi; pos++ {
+			writeLine(w, prefix, f, pos)
+		}
+	}
+	for ; pos < end; pos++ {
+		writeLine(w, "  ", f, pos)
+	}
+}
+
+// contextRange formats the half-open line range [start, end) for a context
+// hunk header as "first,last", or a single number when they coincide.
+func contextRange(start, end int) string {
+	switch {
+	case end-start == 0:
+		return fmt.Sprintf("%d", start)
+	case end-start == 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, end)
+	}
+}
+
+// writeNormal prints the changes in the classic normal format.
+func writeNormal(w io.Writer, a, b *file, changes []change) {
+	for _, c := range changes {
+		switch {
+		case c.b0 == c.b1:
+			fmt.Fprintf(w, "%sd%d\n", normalRange(c.a0, c.a1), c.b0)
+		case c.a0 == c.a1:
+			fmt.Fprintf(w, "%da%s\n", c.a0, normalRange(c.b0, c.b1))
+		default:
+			fmt.Fprintf(w, "%sc%s\n", normalRange(c.a0, c.a1), normalRange(c.b0, c.b1))
+		}
+		for i := c.a0; i < c.a1; i++ {
+			writeLine(w, "< ", a, i)
+		}
+		if c.a0 != c.a1 && c.b0 != c.b1 {
+			fmt.Fprintln(w, "---")
+		}
+		for i := c.b0; i < c.b1; i++ {
+			writeLine(w, "> ", b, i)
+		}
+	}
+}
+
+// normalRange formats the non-empty half-open line range [start, end)
+// as used by the normal output format.
+func normalRange(start, end int) string {
+	if end-start == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end)
+}
@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles stores the two inputs in a temp dir and returns their paths.
+func writeFiles(t *testing.T, a, b string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	pathA := filepath.Join(dir, "a.txt")
+	pathB := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(pathA, []byte(a), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", pathA, err)
+	}
+	if err := os.WriteFile(pathB, []byte(b), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", pathB, err)
+	}
+	return pathA, pathB
+}
+
+// hunks runs diff with the given flags and returns the output without the
+// two file header lines, whose timestamps vary between runs.
+func hunks(t *testing.T, flags []string, a, b string) (string, int) {
+	t.Helper()
+	pathA, pathB := writeFiles(t, a, b)
+	var stdout, stderr bytes.Buffer
+	code := run(append(flags, pathA, pathB), nil, &stdout, &stderr)
+	lines := strings.SplitAfter(stdout.String(), "\n")
+	if len(lines) < 2 {
+		return "", code
+	}
+	return strings.Join(lines[2:], ""), code
+}
+
+func TestUnifiedAddedLine(t *testing.T) {
+	got, code := hunks(t, []string{"-u"}, "a\nb\nc\n", "a\nb\nnew\nc\n")
+	if code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	expected := "@@ -1,3 +1,4 @@\n a\n b\n+new\n c\n"
+	if got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestUnifiedRemovedLine(t *testing.T) {
+	got, _ := hunks(t, []string{"-u"}, "a\nb\nc\nd\n", "a\nc\nd\n")
+	expected := "@@ -1,4 +1,3 @@\n a\n-b\n c\n d\n"
+	if got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestUnifiedChangedLineSeparateHunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"
+	got, _ := hunks(t, []string{"-U", "1"}, a, b)
+	expected := "@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -9,2 +9,2 @@\n 9\n-10\n+ten\n"
+	if got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestUnifiedNoNewlineAtEnd(t *testing.T) {
+	got, _ := hunks(t, []string{"-u"}, "a\nb", "a\nb\n")
+	expected := "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n"
+	if got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestContextFormat(t *testing.T) {
+	got, _ := hunks(t, []string{"-c"}, "a\nb\nc\n", "a\nB\nc\n")
+	expected := "***************\n*** 1,3 ****\n  a\n! b\n  c\n--- 1,3 ----\n  a\n! B\n  c\n"
+	if got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestNormalFormat(t *testing.T) {
+	pathA, pathB := writeFiles(t, "a\nb\nc\n", "a\nx\nc\nd\n")
+	var stdout, stderr bytes.Buffer
+	run([]string{pathA, pathB}, nil, &stdout, &stderr)
+	expected := "2c2\n< b\n---\n> x\n3a4\n> d\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestIdenticalAndIgnoreFlags(t *testing.T) {
+	tests := []struct {
+		flags []string
+		a, b  string
+		code  int
+	}{
+		{nil, "same\n", "same\n", 0},
+		{nil, "Hello\n", "hello\n", 1},
+		{[]string{"-i"}, "Hello\n", "hello\n", 0},
+		{[]string{"-w"}, "a  b\n", "a b \n", 0},
+		{[]string{"-i", "-w"}, "A B\n", "ab\n", 0},
+	}
+	for _, tt := range tests {
+		pathA, pathB := writeFiles(t, tt.a, tt.b)
+		var stdout, stderr bytes.Buffer
+		if code := run(append(tt.flags, pathA, pathB), nil, &stdout, &stderr); code != tt.code {
+			t.Errorf("diff %v %q %q: expected exit code %d but got %d", tt.flags, tt.a, tt.b, tt.code, code)
+		}
+	}
+}
+
+func TestBriefAndErrors(t *testing.T) {
+	pathA, pathB := writeFiles(t, "a\n", "b\n")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-q", pathA, pathB}, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	expected := "Files " + pathA + " and " + pathB + " differ\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	if code := run([]string{pathA, "/nonexistent"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for a missing file but got %d", code)
+	}
+}
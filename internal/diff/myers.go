@@ -0,0 +1,127 @@
+package diff
+
+// change describes a run of lines that differ between the two inputs.
+// Lines a[a0:a1] of the first input were replaced by lines b[b0:b1] of the
+// second; either range may be empty for pure insertions or deletions.
+type change struct {
+	a0, a1 int
+	b0, b1 int
+}
+
+// opKind identifies a single step of an edit script.
+type opKind int
+
+const (
+	opEqual  opKind = iota // The line is common to both inputs.
+	opDelete               // The line only exists in the first input.
+	opInsert               // The line only exists in the second input.
+)
+
+// computeChanges returns the differences between a and b as a list of
+// changes ordered by position, using Myers' O(ND) algorithm on the lines.
+func computeChanges(a, b []string) []change {
+	ops := myers(a, b)
+
+	// Collapse consecutive deletions and insertions into change blocks.
+	var changes []change
+	x, y := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i] == opEqual {
+			x++
+			y++
+			i++
+			continue
+		}
+		c := change{a0: x, a1: x, b0: y, b1: y}
+		for ; i < len(ops) && ops[i] != opEqual; i++ {
+			if ops[i] == opDelete {
+				c.a1++
+			} else {
+				c.b1++
+			}
+		}
+		x, y = c.a1, c.b1
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// myers computes a shortest edit script turning a into b.
+// It records the furthest-reaching path for every edit distance d and then
+// walks the recorded frontiers backwards to recover the individual steps.
+func myers(a, b []string) []opKind {
+	n, m := len(a), len(b)
+	total := n + m
+	if total == 0 {
+		return nil
+	}
+
+	// v[offset+k] holds the furthest x reached on diagonal k (where k = x - y).
+	offset := total
+	v := make([]int, 2*total+2)
+	var trace [][]int
+
+search:
+	for d := 0; d <= total; d++ {
+		// Remember the frontier as it was before taking step d.
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Move down: insert a line from b.
+			} else {
+				x = v[offset+k-1] + 1 // Move right: delete a line from a.
+			}
+			y := x - k
+			// Follow the diagonal for as long as the lines match.
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk back from the end, emitting steps in reverse order.
+	var ops []opKind
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, opEqual)
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, opInsert)
+			y--
+		} else {
+			ops = append(ops, opDelete)
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, opEqual)
+		x--
+		y--
+	}
+
+	// Reverse the steps into forward order.
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,57 @@
+// Package dirname implements the functionality for the "dirname" Unix tool.
+package dirname
+
+import (
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For printing the results.
+	"io"      // Provides the writer abstraction used for output.
+	"os"      // For the standard streams.
+	"strings" // For trimming slashes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the dirname functionality.
+// It prints each NAME with its last component removed and returns the
+// exit status: 0 on success, or 2 on invalid usage.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "dirname".
+	fs := flag.NewFlagSet("dirname", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		cli.Fprintf(stderr, "dirname", "missing operand")
+		return cli.ExitUsage
+	}
+	for _, name := range fs.Args() {
+		fmt.Fprintln(stdout, dirname(name))
+	}
+	return cli.ExitSuccess
+}
+
+// dirname returns the directory part of name as POSIX defines it: the
+// last component and the slashes around it are removed, a name without
+// slashes yields ".", and the root stays "/".
+func dirname(name string) string {
+	trimmed := strings.TrimRight(name, "/")
+	if trimmed == "" {
+		if name == "" {
+			return "."
+		}
+		return "/" // The name is made only of slashes.
+	}
+	i := strings.LastIndexByte(trimmed, '/')
+	if i < 0 {
+		return "."
+	}
+	if dir := strings.TrimRight(trimmed[:i], "/"); dir != "" {
+		return dir
+	}
+	return "/"
+}
@@ -0,0 +1,40 @@
+package dirname
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDirname(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"/a/b.txt", "/a"},
+		{"/a/b/", "/a"},
+		{"/a//b//", "/a"},
+		{"a/b", "a"},
+		{"file", "."},
+		{".", "."},
+		{"..", "."},
+		{"/", "/"},
+		{"//", "/"},
+		{"/usr", "/"},
+		{"", "."},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run([]string{tt.name}, &stdout, &stderr)
+		if stdout.String() != tt.expected+"\n" || status != 0 {
+			t.Errorf("dirname %q: Expected %q (0) but got %q (%d)", tt.name, tt.expected+"\n", stdout.String(), status)
+		}
+	}
+}
+
+func TestMultipleOperands(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	run([]string{"/x/y", "z"}, &stdout, &stderr)
+	if expected := "/x\n.\n"; stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
@@ -0,0 +1,196 @@
+// Package du implements the functionality for the "du" Unix tool.
+package du
+
+import (
+	"bufio"         // For buffering the output.
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For formatting the sizes.
+	"io"            // Provides the writer abstraction used for output.
+	"io/fs"         // For the directory entries used by the walk.
+	"os"            // For interacting with the file system and OS I/O.
+	"path/filepath" // For walking directory trees.
+	"strconv"       // For formatting sizes in kilobytes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"      // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo" // Platform-specific file metadata.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	summarize bool // -s: print only the total of each operand.
+	human     bool // -h: print sizes like 1.5K, 20M and 3.0G.
+	all       bool // -a: print files as well as directories.
+	total     bool // -c: print a grand total after all operands.
+}
+
+// fileID identifies a file across hard links.
+type fileID struct {
+	dev, ino uint64
+}
+
+// pending is a directory whose total is still being added up.
+type pending struct {
+	path string // Where the directory is.
+	size int64  // Bytes used by the directory and what has been seen inside it.
+}
+
+// usage adds up the space used below each operand.
+type usage struct {
+	w      *bufio.Writer
+	stderr io.Writer
+	opts   *options
+	seen   map[fileID]bool // Files with several links that were already counted.
+}
+
+// Run is the entry point for the du functionality.
+// It prints the disk space used by each directory below the operands (the
+// current directory by default) and returns the exit status: 0 on success,
+// or 1 if any file or directory could not be examined.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "du".
+	fset := flag.NewFlagSet("du", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-s" flag to print only a total for each operand.
+	fset.BoolVar(&opts.summarize, "s", false, "display only a total for each argument")
+	// Define the "-h" flag to print sizes in human-readable units.
+	fset.BoolVar(&opts.human, "h", false, "print sizes in human readable format (e.g., 1K 234M 2G)")
+	// Define the "-a" flag to list files as well as directories.
+	fset.BoolVar(&opts.all, "a", false, "write counts for all files, not just directories")
+	// Define the "-c" flag to print a grand total.
+	fset.BoolVar(&opts.total, "c", false, "produce a grand total")
+	fset.Parse(args)
+
+	// Measure the current directory when no operands are given.
+	roots := fset.Args()
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	u := &usage{w: bufio.NewWriter(stdout), stderr: stderr, opts: &opts, seen: map[fileID]bool{}}
+	status := cli.ExitSuccess
+	var total int64
+	for _, root := range roots {
+		size, ok := u.walk(root)
+		if !ok {
+			status = cli.ExitFailure
+		}
+		total += size
+	}
+	if opts.total {
+		u.print(total, "total")
+	}
+	if err := u.w.Flush(); err != nil {
+		cli.Fprintf(stderr, "du", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// walk adds up the space used by root and everything below it, printing
+// the running totals the options ask for, and returns root's total. The
+// result is false if anything could not be examined.
+func (u *usage) walk(root string) (int64, bool) {
+	ok := true
+	// Directories are printed after their contents, so the walk keeps the
+	// chain of directories from root to the current entry. Their totals
+	// are settled once the walk moves on to somewhere outside them.
+	var stack []pending
+	var rootSize int64
+	closeDir := func() {
+		dir := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if len(stack) > 0 {
+			stack[len(stack)-1].size += dir.size
+		} else {
+			rootSize = dir.size
+		}
+		if !u.opts.summarize || len(stack) == 0 {
+			u.print(dir.size, dir.path)
+		}
+	}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if entry == nil {
+				// The root itself could not be examined.
+				return err
+			}
+			// A directory that cannot be read was already counted on the
+			// walk's first visit; only its contents are missing.
+			u.error("cannot read directory '%s': %v", path, cli.Unwrap(err))
+			ok = false
+			return nil
+		}
+		if path != root {
+			for filepath.Dir(path) != filepath.Clean(stack[len(stack)-1].path) {
+				closeDir()
+			}
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			u.error("cannot access '%s': %v", path, cli.Unwrap(err))
+			ok = false
+			return nil
+		}
+		size := u.size(info)
+		if entry.IsDir() {
+			stack = append(stack, pending{path: path, size: size})
+			return nil
+		}
+		if len(stack) == 0 {
+			// The root is a file, which is always printed.
+			rootSize = size
+			u.print(size, path)
+			return nil
+		}
+		stack[len(stack)-1].size += size
+		if u.opts.all && !u.opts.summarize {
+			u.print(size, path)
+		}
+		return nil
+	})
+	if err != nil {
+		u.error("cannot access '%s': %v", root, cli.Unwrap(err))
+		return 0, false
+	}
+	for len(stack) > 0 {
+		closeDir()
+	}
+	return rootSize, ok
+}
+
+// size returns the number of bytes allocated to the file described by
+// info, or 0 if another link to it has already been counted.
+func (u *usage) size(info fs.FileInfo) int64 {
+	if !info.IsDir() && fileinfo.Links(info) > 1 {
+		id := fileID{dev: fileinfo.Device(info), ino: fileinfo.Inode(info)}
+		if u.seen[id] {
+			return 0
+		}
+		u.seen[id] = true
+	}
+	return fileinfo.Blocks(info) * 512
+}
+
+// print writes one line of output: the size of path, followed by path.
+func (u *usage) print(size int64, path string) {
+	if u.opts.human {
+		fmt.Fprintf(u.w, "%s\t%s\n", fileinfo.HumanSize(size), path)
+		return
+	}
+	// Sizes are shown in kilobytes, rounded up.
+	fmt.Fprintf(u.w, "%s\t%s\n", strconv.FormatInt((size+1023)/1024, 10), path)
+}
+
+// error reports a problem without mixing it into buffered output.
+func (u *usage) error(format string, args ...any) {
+	u.w.Flush()
+	cli.Fprintf(u.stderr, "du", format, args...)
+}
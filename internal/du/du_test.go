@@ -0,0 +1,113 @@
+package du
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo"
+)
+
+// makeTree creates the given files (and directories, for names ending in
+// "/") inside a fresh temp dir and returns its path. Each file holds as
+// many bytes as the length of its name times 1000, so sizes differ.
+func makeTree(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatalf("Failed to create %s: %v", path, err)
+			}
+			continue
+		}
+		content := bytes.Repeat([]byte("x"), 1000*len(name))
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	return dir
+}
+
+// kilobytes returns the space used by the given paths, as du prints it.
+func kilobytes(t *testing.T, paths ...string) string {
+	t.Helper()
+	var size int64
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", path, err)
+		}
+		size += fileinfo.Blocks(info) * 512
+	}
+	return fmt.Sprint((size + 1023) / 1024)
+}
+
+func TestTree(t *testing.T) {
+	root := makeTree(t, "sub/", "sub/deep/", "sub/deep/file", "sub/a", "top")
+	sub := filepath.Join(root, "sub")
+	deep := filepath.Join(sub, "deep")
+	deepFile := filepath.Join(deep, "file")
+	subFile := filepath.Join(sub, "a")
+	top := filepath.Join(root, "top")
+
+	deepSize := kilobytes(t, deep, deepFile)
+	subSize := kilobytes(t, sub, subFile, deep, deepFile)
+	rootSize := kilobytes(t, root, top, sub, subFile, deep, deepFile)
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{root}, deepSize + "\t" + deep + "\n" + subSize + "\t" + sub + "\n" + rootSize + "\t" + root + "\n"},
+		{[]string{"-a", sub}, kilobytes(t, subFile) + "\t" + subFile + "\n" +
+			kilobytes(t, deepFile) + "\t" + deepFile + "\n" +
+			deepSize + "\t" + deep + "\n" + subSize + "\t" + sub + "\n"},
+		{[]string{"-s", root}, rootSize + "\t" + root + "\n"},
+		{[]string{"-s", "-c", sub, top}, subSize + "\t" + sub + "\n" +
+			kilobytes(t, top) + "\t" + top + "\n" +
+			kilobytes(t, sub, subFile, deep, deepFile, top) + "\ttotal\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, &stdout, &stderr); status != 0 {
+			t.Fatalf("du %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("du %v: Expected %q but got %q", tt.args, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestHuman(t *testing.T) {
+	root := makeTree(t, "file")
+	info, err := os.Lstat(root)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", root, err)
+	}
+	file, err := os.Lstat(filepath.Join(root, "file"))
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", root, err)
+	}
+	size := (fileinfo.Blocks(info) + fileinfo.Blocks(file)) * 512
+	var stdout, stderr bytes.Buffer
+	run([]string{"-s", "-h", root}, &stdout, &stderr)
+	expected := fileinfo.HumanSize(size) + "\t" + root + "\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{missing}, &stdout, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	if !strings.HasPrefix(stderr.String(), "du: cannot access '"+missing+"': ") {
+		t.Errorf("Expected an error for %s but got %q", missing, stderr.String())
+	}
+}
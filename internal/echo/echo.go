@@ -2,21 +2,121 @@
 package echo
 
 import (
-	"os"      // Used for interacting with standard I/O and process exit.
+	"flag"    // Used to parse command-line flags.
+	"io"      // Provides the writer abstraction and io.Discard.
+	"os"      // Used for access to standard output.
 	"strings" // Provides string manipulation functions.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
 )
 
 // Run concatenates the provided arguments and writes them to standard output.
-func Run(args []string) {
-	// Join all command-line arguments into a single string separated by spaces.
-	output := strings.Join(args, " ")
-
-	// Write the output to standard output (stdout) with a trailing newline.
-	// os.Stdout.WriteString returns the number of bytes written and an error.
-	// We check if there was an error during the write operation.
-	if _, err := os.Stdout.WriteString(output + "\n"); err != nil {
-		// If there is an error, exit the program with a non-zero status code.
-		// Note: Exiting immediately may bypass deferred clean-up.
-		os.Exit(1)
+// It returns the exit status: 0 on success, or 1 if writing failed.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	if err := RunWithWriter(stdout, args); err != nil {
+		cli.Fprintf(stderr, "echo", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// RunWithWriter concatenates the provided arguments and writes them to w.
+// It returns any error encountered while writing.
+func RunWithWriter(w io.Writer, args []string) error {
+	// Create a new FlagSet for parsing command-line options specific to "echo".
+	fs := flag.NewFlagSet("echo", flag.ContinueOnError)
+	// Define the "-n" flag to suppress the trailing newline.
+	noNewline := fs.Bool("n", false, "do not output the trailing newline")
+	// Define the "-e" flag to enable interpretation of backslash escapes.
+	escapes := fs.Bool("e", false, "enable interpretation of backslash escapes")
+	// Define the "-s" flag to choose the string placed between arguments.
+	separator := fs.String("s", " ", "separate arguments with `SEP`")
+	// Unknown options are not errors for echo, so keep the flag package quiet.
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		// An unrecognized option is printed literally, like any other word.
+		*noNewline = false
+		*escapes = false
+		*separator = " "
+	} else {
+		// Only the remaining non-flag arguments are echoed.
+		args = fs.Args()
+	}
+
+	// Join all command-line arguments into a single string using the separator.
+	output := strings.Join(args, *separator)
+	if *escapes {
+		// Decode escape sequences such as \t and \n in the joined text.
+		// A \c escape ends the output early and also drops the newline.
+		var stop bool
+		output, stop = interpretEscapes(output)
+		if stop {
+			*noNewline = true
+		}
+	}
+	if !*noNewline {
+		output += "\n"
+	}
+
+	// Write the output to the destination writer.
+	_, err := io.WriteString(w, output)
+	return err
+}
+
+// interpretEscapes decodes the C-style backslash escapes understood by
+// "echo -e": \\, \a, \b, \f, \n, \r, \t, \v and \0NNN (up to three octal
+// digits). Unknown escapes and a trailing lone backslash are kept literally.
+// A \c escape stops decoding: the text before it is returned along with
+// true, telling the caller to produce no further output.
+func interpretEscapes(s string) (string, bool) {
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		// Copy ordinary characters, and a backslash with nothing after it, as-is.
+		if runes[i] != '\\' || i+1 == len(runes) {
+			b.WriteRune(runes[i])
+			continue
+		}
+
+		i++ // Skip the backslash and look at the escape character.
+		switch runes[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'c':
+			// Produce no further output, including the trailing newline.
+			return b.String(), true
+		case '0':
+			// Consume up to three octal digits following the zero.
+			value := 0
+			for n := 0; n < 3 && i+1 < len(runes) && runes[i+1] >= '0' && runes[i+1] <= '7'; n++ {
+				i++
+				value = value*8 + int(runes[i]-'0')
+			}
+			b.WriteByte(byte(value))
+		default:
+			// Not a recognized escape: emit the backslash and character unchanged.
+			b.WriteByte('\\')
+			b.WriteRune(runes[i])
+		}
 	}
+	return b.String(), false
 }
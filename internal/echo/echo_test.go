@@ -2,33 +2,120 @@ package echo
 
 import (
 	"bytes"
-	"io"
-	"os"
+	"errors"
 	"testing"
 )
 
+// captureRun runs RunWithWriter with the given arguments and returns what
+// it wrote.
+func captureRun(t *testing.T, args []string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := RunWithWriter(&buf, args); err != nil {
+		t.Fatalf("RunWithWriter returned error: %v", err)
+	}
+	return buf.String()
+}
+
 func TestRun(t *testing.T) {
-	oldStdout := os.Stdout
+	expected := "Hello World\n"
+	if got := captureRun(t, []string{"Hello", "World"}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
 
-	r, w, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("Failed to create pipe: %v", err)
+func TestRunNoNewline(t *testing.T) {
+	expected := "foo"
+	if got := captureRun(t, []string{"-n", "foo"}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
 	}
-	os.Stdout = w
 
-	Run([]string{"Hello", "World"})
+	expected = "foo bar"
+	if got := captureRun(t, []string{"-n", "foo", "bar"}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
 
-	w.Close()
-	os.Stdout = oldStdout
+func TestRunEscapes(t *testing.T) {
+	expected := "a\tb\nc\n"
+	if got := captureRun(t, []string{"-e", `a\tb\nc`}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
 
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, r); err != nil {
-		t.Fatalf("Failed to read from pipe: %v", err)
+	// Without -e the backslashes are written literally.
+	expected = `a\tb` + "\n"
+	if got := captureRun(t, []string{`a\tb`}); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
 	}
-	r.Close()
+}
 
-	expected := "Hello World\n"
-	if got := buf.String(); got != expected {
+func TestInterpretEscapes(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{`trailing\`, `trailing\`},
+		{`back\\slash`, `back\slash`},
+		{`\r\a\b\f\v`, "\r\a\b\f\v"},
+		{`\0101\0`, "A\x00"},
+		{`\0777`, "\xff"},
+		{`\q`, `\q`},
+	}
+	for _, tt := range tests {
+		if got, _ := interpretEscapes(tt.in); got != tt.expected {
+			t.Errorf("interpretEscapes(%q): expected %q but got %q", tt.in, tt.expected, got)
+		}
+	}
+}
+
+func TestRunEscapeStop(t *testing.T) {
+	expected := "hello"
+	if got := captureRun(t, []string{"-e", `hello\cworld`, "more"}); got != expected {
 		t.Errorf("Expected %q but got %q", expected, got)
 	}
 }
+
+func TestRunSeparator(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"a", "b", "c"}, "a b c\n"},
+		{[]string{"-s", ",", "a", "b", "c"}, "a,b,c\n"},
+		{[]string{"-s", " :: ", "a", "b", "c"}, "a :: b :: c\n"},
+	}
+	for _, tt := range tests {
+		if got := captureRun(t, tt.args); got != tt.expected {
+			t.Errorf("echo %q: expected %q but got %q", tt.args, tt.expected, got)
+		}
+	}
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestRunWithWriterError(t *testing.T) {
+	if err := RunWithWriter(failingWriter{}, []string{"foo"}); err == nil {
+		t.Errorf("Expected an error from a failing writer but got nil")
+	}
+}
+
+func TestRunExitStatus(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"foo"}, &stdout, &stderr); status != 0 || stdout.String() != "foo\n" {
+		t.Errorf("Expected %q with status 0 but got %q with status %d", "foo\n", stdout.String(), status)
+	}
+
+	stderr.Reset()
+	if status := run([]string{"foo"}, failingWriter{}, &stderr); status != 1 {
+		t.Errorf("Expected status 1 for a failing writer but got %d", status)
+	}
+	expected := "echo: write error: write failed\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
@@ -0,0 +1,129 @@
+// Package expand implements the functionality for the "expand" Unix tool.
+package expand
+
+import (
+	"bufio"        // Provides buffered reading and writing.
+	"flag"         // Used to parse command-line flags.
+	"fmt"          // For formatting error messages.
+	"io"           // Provides the reader and writer abstractions used for input and output.
+	"os"           // For interacting with the file system and OS I/O.
+	"strings"      // For producing runs of spaces.
+	"unicode/utf8" // For counting columns in characters rather than bytes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the expand functionality.
+// It copies each file (or stdin) with tabs replaced by spaces, and returns
+// the exit status: 0 on success, 1 if any file could not be read, or 2
+// for invalid tab stops.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "expand".
+	fset := flag.NewFlagSet("expand", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-t" flag to set the tab stops.
+	list := fset.String("t", "8", "have tabs `N` characters apart, or use the comma-separated LIST of positions")
+	// Define the "-i" flag to leave tabs after the first non-blank alone.
+	initial := fset.Bool("i", false, "do not convert tabs after non blanks")
+	fset.Parse(args)
+
+	stops, err := ParseTabStops(*list)
+	if err != nil {
+		cli.Fprintf(stderr, "expand", "%v", err)
+		return cli.ExitUsage
+	}
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for _, file := range files {
+		if err := expandFile(out, file, stdin, stops, *initial); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "expand", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "expand", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// expandFile copies the named file, or stdin for "-", to w with its tabs
+// expanded.
+func expandFile(w *bufio.Writer, file string, stdin io.Reader, stops TabStops, initial bool) error {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+		}
+		defer f.Close()
+		r = f
+	}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			w.WriteString(expandLine(line, stops, initial))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+		}
+	}
+}
+
+// expandLine returns line with each tab replaced by the spaces that reach
+// the next tab stop from the tab's column. Past the last stop of a list,
+// a tab becomes a single space. With initial, only tabs before the first
+// non-blank character are replaced.
+func expandLine(line string, stops TabStops, initial bool) string {
+	var b strings.Builder
+	col := 0
+	leading := true
+	for len(line) > 0 {
+		r, size := utf8.DecodeRuneInString(line)
+		switch {
+		case r == '\t' && (leading || !initial):
+			next, ok := stops.Next(col)
+			if !ok {
+				next = col + 1
+			}
+			b.WriteString(strings.Repeat(" ", next-col))
+			col = next
+		case r == '\t':
+			// Unexpanded tabs still move the column on.
+			b.WriteByte('\t')
+			if next, ok := stops.Next(col); ok {
+				col = next
+			} else {
+				col++
+			}
+		case r == '\b':
+			b.WriteByte('\b')
+			col = max(col-1, 0)
+		default:
+			b.WriteString(line[:size])
+			col++
+		}
+		if r != ' ' && r != '\t' {
+			leading = false
+		}
+		line = line[size:]
+	}
+	return b.String()
+}
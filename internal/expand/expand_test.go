@@ -0,0 +1,63 @@
+package expand
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{nil, "a\tb\n", "a       b\n"},
+		{nil, "abcdefgh\tx\n", "abcdefgh        x\n"},
+		{nil, "  \t x\tyz\n", "         x      yz\n"},
+		{[]string{"-t", "4"}, "\tab\tc\n", "    ab  c\n"},
+		{[]string{"-t", "2,5"}, "\ta\tb\tc\n", "  a  b c\n"},
+		{[]string{"-i"}, "\tx\ty\n", "        x\ty\n"},
+		{nil, "no tabs", "no tabs"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, strings.NewReader(tt.input), &stdout, &stderr); status != 0 {
+			t.Fatalf("expand %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("expand %v: Expected %q but got %q", tt.args, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestParseTabStops(t *testing.T) {
+	tests := []struct {
+		list string
+		err  string
+	}{
+		{"4", ""},
+		{"2,4 8", ""},
+		{"0", "tab size cannot be 0"},
+		{"4,2", "tab sizes must be ascending"},
+		{"4x", "tab size contains invalid character(s): '4x'"},
+	}
+	for _, tt := range tests {
+		_, err := ParseTabStops(tt.list)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.err {
+			t.Errorf("ParseTabStops(%q): Expected error %q but got %q", tt.list, tt.err, got)
+		}
+	}
+
+	stops, _ := ParseTabStops("3,7")
+	if next, ok := stops.Next(3); !ok || next != 7 {
+		t.Errorf("Expected the stop after column 3 to be 7 but got %d, %v", next, ok)
+	}
+	if _, ok := stops.Next(7); ok {
+		t.Errorf("Expected no stop after the last one")
+	}
+}
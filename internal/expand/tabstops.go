@@ -0,0 +1,75 @@
+package expand
+
+import (
+	"errors"  // For reporting invalid tab stop lists.
+	"fmt"     // For formatting those errors.
+	"strconv" // For parsing tab positions.
+	"strings" // For splitting tab stop lists.
+)
+
+// TabStops are the columns where tabs stop, numbered from 0: either every
+// interval columns, or the explicit columns of a list.
+type TabStops struct {
+	interval int   // Distance between stops, or 0 if stops is used.
+	stops    []int // Ascending stop columns, for a list.
+}
+
+// ParseTabStops parses the argument of -t: a single number N for a stop
+// every N columns, or a list of ascending positions separated by commas
+// or blanks, such as "4,8,12".
+func ParseTabStops(s string) (TabStops, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return TabStops{}, errors.New("tab size cannot be 0")
+	}
+	var stops []int
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return TabStops{}, fmt.Errorf("tab size contains invalid character(s): '%s'", field)
+		}
+		if n == 0 {
+			return TabStops{}, errors.New("tab size cannot be 0")
+		}
+		if len(stops) > 0 && n <= stops[len(stops)-1] {
+			return TabStops{}, errors.New("tab sizes must be ascending")
+		}
+		stops = append(stops, n)
+	}
+	if len(stops) == 1 {
+		return TabStops{interval: stops[0]}, nil
+	}
+	return TabStops{stops: stops}, nil
+}
+
+// Next returns the first tab stop after col; ok is false if col is at or
+// past the last stop of a list.
+func (t TabStops) Next(col int) (stop int, ok bool) {
+	if t.interval > 0 {
+		return (col/t.interval + 1) * t.interval, true
+	}
+	for _, stop := range t.stops {
+		if stop > col {
+			return stop, true
+		}
+	}
+	return 0, false
+}
+
+// IsStop reports whether col is a tab stop.
+func (t TabStops) IsStop(col int) bool {
+	if col <= 0 {
+		return false
+	}
+	if t.interval > 0 {
+		return col%t.interval == 0
+	}
+	for _, stop := range t.stops {
+		if stop == col {
+			return true
+		}
+	}
+	return false
+}
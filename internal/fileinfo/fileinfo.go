@@ -0,0 +1,9 @@
+// Package fileinfo provides the file metadata that os.FileInfo does not
+// expose portably, such as link counts, owners, inodes and access and
+// change times. On platforms without a Unix stat structure, each function
+// falls back to a neutral value.
+package fileinfo
+
+// defaultBlockSize is the I/O block size reported when the platform does
+// not record one.
+const defaultBlockSize = 4096
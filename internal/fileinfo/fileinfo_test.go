@@ -0,0 +1,72 @@
+package fileinfo
+
+import (
+	"os"
+	"testing"
+)
+
+func TestModeString(t *testing.T) {
+	tests := []struct {
+		mode os.FileMode
+		want string
+	}{
+		{0o644, "-rw-r--r--"},
+		{os.ModeDir | 0o755, "drwxr-xr-x"},
+		{os.ModeDir | os.ModeSticky | 0o777, "drwxrwxrwt"},
+		{os.ModeDir | os.ModeSticky | 0o770, "drwxrwx--T"},
+		{os.ModeSetuid | 0o755, "-rwsr-xr-x"},
+		{os.ModeSetuid | 0o644, "-rwSr--r--"},
+		{os.ModeSetgid | 0o755, "-rwxr-sr-x"},
+		{os.ModeSetgid | 0o640, "-rw-r-S---"},
+		{os.ModeDevice | os.ModeCharDevice | 0o666, "crw-rw-rw-"},
+		{os.ModeDevice | 0o660, "brw-rw----"},
+		{os.ModeNamedPipe | 0o644, "prw-r--r--"},
+		{os.ModeSocket | 0o755, "srwxr-xr-x"},
+	}
+	for _, tt := range tests {
+		if got := ModeString(tt.mode); got != tt.want {
+			t.Errorf("Expected %q for %v but got %q", tt.want, tt.mode, got)
+		}
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{0, "0"},
+		{1023, "1023"},
+		{1024, "1.0K"},
+		{1536, "1.5K"},
+		{1024*1024 - 1, "1.0M"},
+		{1024 * 1024, "1.0M"},
+		{2411724, "2.3M"},
+		{4 * 1024 * 1024 * 1024, "4.0G"},
+	}
+	for _, tt := range tests {
+		if got := HumanSize(tt.n); got != tt.expected {
+			t.Errorf("HumanSize(%d): expected %q but got %q", tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestOctal(t *testing.T) {
+	tests := []struct {
+		bits uint32
+		mode os.FileMode
+	}{
+		{0o644, 0o644},
+		{0o4755, os.ModeSetuid | 0o755},
+		{0o2750, os.ModeSetgid | 0o750},
+		{0o1777, os.ModeSticky | 0o777},
+	}
+	for _, tt := range tests {
+		if got := FromOctal(tt.bits); got != tt.mode {
+			t.Errorf("FromOctal(%o): Expected %v but got %v", tt.bits, tt.mode, got)
+		}
+		if got := Octal(tt.mode | os.ModeDir); got != tt.bits {
+			t.Errorf("Octal(%v): Expected %o but got %o", tt.mode, tt.bits, got)
+		}
+	}
+}
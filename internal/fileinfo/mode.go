@@ -0,0 +1,92 @@
+package fileinfo
+
+import "os" // For the file mode bits.
+
+// TypeChar returns the character ls and stat use for the file type in mode:
+// 'd' for directories, 'l' for symbolic links, 'c' and 'b' for character
+// and block devices, 'p' for FIFOs, 's' for sockets and '-' otherwise.
+func TypeChar(mode os.FileMode) byte {
+	switch {
+	case mode&os.ModeDir != 0:
+		return 'd'
+	case mode&os.ModeSymlink != 0:
+		return 'l'
+	case mode&os.ModeCharDevice != 0:
+		return 'c' // Checked before ModeDevice, which character devices also set.
+	case mode&os.ModeDevice != 0:
+		return 'b'
+	case mode&os.ModeNamedPipe != 0:
+		return 'p'
+	case mode&os.ModeSocket != 0:
+		return 's'
+	default:
+		return '-'
+	}
+}
+
+// ModeString renders mode as the ten-character string shown by ls -l,
+// e.g. "drwxrwxrwt". os.FileMode.String is not used because it reports
+// the setuid, setgid and sticky bits as extra prefix letters rather than
+// in the execute slots: s/S for setuid and setgid, t/T for sticky, with
+// the uppercase form used when the underlying execute bit is not set.
+func ModeString(mode os.FileMode) string {
+	const rwx = "rwxrwxrwx"
+	buf := []byte{TypeChar(mode)}
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			buf = append(buf, rwx[i])
+		} else {
+			buf = append(buf, '-')
+		}
+	}
+
+	// special overlays the execute slot at i with lower (when executable)
+	// or upper (when not) if the given special bit is set.
+	special := func(bit os.FileMode, i int, lower, upper byte) {
+		if mode&bit == 0 {
+			return
+		}
+		if buf[i] == 'x' {
+			buf[i] = lower
+		} else {
+			buf[i] = upper
+		}
+	}
+	special(os.ModeSetuid, 3, 's', 'S')
+	special(os.ModeSetgid, 6, 's', 'S')
+	special(os.ModeSticky, 9, 't', 'T')
+	return string(buf)
+}
+
+// Octal returns the permission bits of mode as the traditional Unix octal
+// value, with the setuid, setgid and sticky bits at 04000, 02000 and
+// 01000.
+func Octal(mode os.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 0o1000
+	}
+	return bits
+}
+
+// FromOctal is the inverse of Octal: it returns the FileMode for the
+// Unix permission bits in bits, of which only the lowest twelve are used.
+func FromOctal(bits uint32) os.FileMode {
+	mode := os.FileMode(bits & 0o777)
+	if bits&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
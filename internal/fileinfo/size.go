@@ -0,0 +1,24 @@
+package fileinfo
+
+import (
+	"fmt"     // For formatting the scaled value.
+	"math"    // For rounding the scaled value.
+	"strconv" // For formatting small sizes.
+)
+
+// HumanSize formats a byte count using powers of 1024 with one decimal
+// place, e.g. 1536 becomes "1.5K". Sizes below 1024 are printed as is.
+func HumanSize(n int64) string {
+	if n < 1024 {
+		return strconv.FormatInt(n, 10)
+	}
+	value := float64(n)
+	units := "KMGTPE"
+	unit := -1
+	// Move up a unit while the value would still print as 1024 or more.
+	for math.Round(value*10)/10 >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f%c", value, units[unit])
+}
@@ -0,0 +1,14 @@
+//go:build darwin || freebsd || ios || netbsd
+
+package fileinfo
+
+import (
+	"syscall" // For the stat structure.
+	"time"    // For converting its timestamps.
+)
+
+// statTimes returns the access and status change times recorded in stat.
+func statTimes(stat *syscall.Stat_t) (atime, ctime time.Time) {
+	return time.Unix(int64(stat.Atimespec.Sec), int64(stat.Atimespec.Nsec)),
+		time.Unix(int64(stat.Ctimespec.Sec), int64(stat.Ctimespec.Nsec))
+}
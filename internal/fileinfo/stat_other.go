@@ -0,0 +1,51 @@
+//go:build !unix
+
+package fileinfo
+
+import (
+	"os"   // For the FileInfo type.
+	"time" // For the access and change timestamps.
+)
+
+// Links reports a single link on platforms without Unix link counts.
+func Links(info os.FileInfo) uint64 {
+	return 1
+}
+
+// Owner reports unknown ownership on platforms without Unix IDs.
+func Owner(info os.FileInfo) (uid, gid int, ok bool) {
+	return -1, -1, false
+}
+
+// Blocks estimates the 512-byte blocks allocated to info from its size.
+func Blocks(info os.FileInfo) int64 {
+	return (info.Size() + 511) / 512
+}
+
+// BlockSize reports a typical I/O block size on platforms that do not
+// record one.
+func BlockSize(info os.FileInfo) int64 {
+	return defaultBlockSize
+}
+
+// Inode reports no inode number on platforms without them.
+func Inode(info os.FileInfo) uint64 {
+	return 0
+}
+
+// Device reports no device number on platforms without them.
+func Device(info os.FileInfo) uint64 {
+	return 0
+}
+
+// Atime falls back to the modification time on platforms without a Unix
+// stat structure.
+func Atime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
+
+// Ctime falls back to the modification time on platforms without a Unix
+// stat structure.
+func Ctime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
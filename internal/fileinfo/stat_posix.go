@@ -0,0 +1,14 @@
+//go:build unix && !(darwin || freebsd || ios || netbsd)
+
+package fileinfo
+
+import (
+	"syscall" // For the stat structure.
+	"time"    // For converting its timestamps.
+)
+
+// statTimes returns the access and status change times recorded in stat.
+func statTimes(stat *syscall.Stat_t) (atime, ctime time.Time) {
+	return time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec)),
+		time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+}
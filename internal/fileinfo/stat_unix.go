@@ -0,0 +1,88 @@
+//go:build unix
+
+package fileinfo
+
+import (
+	"os"      // For the FileInfo type.
+	"syscall" // To access the underlying stat structure.
+	"time"    // For the access and change timestamps.
+)
+
+// Links returns the number of hard links to the file described by info.
+func Links(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 1
+	}
+	return uint64(stat.Nlink)
+}
+
+// Owner returns the numeric owner and group IDs of info; ok is false if
+// they are unknown.
+func Owner(info os.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
+
+// Blocks returns the number of 512-byte blocks allocated to info.
+func Blocks(info os.FileInfo) int64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return (info.Size() + 511) / 512
+	}
+	return int64(stat.Blocks)
+}
+
+// BlockSize returns the preferred I/O block size for the file described
+// by info.
+func BlockSize(info os.FileInfo) int64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return defaultBlockSize
+	}
+	return int64(stat.Blksize)
+}
+
+// Inode returns the inode number of the file described by info.
+func Inode(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+	return uint64(stat.Ino)
+}
+
+// Device returns the number of the device holding the file described by
+// info.
+func Device(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+	return uint64(stat.Dev)
+}
+
+// Atime returns the last access time of info, or its modification time
+// if the stat structure is unavailable.
+func Atime(info os.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+	access, _ := statTimes(stat)
+	return access
+}
+
+// Ctime returns the last status change time of info, or its modification
+// time if the stat structure is unavailable.
+func Ctime(info os.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+	_, change := statTimes(stat)
+	return change
+}
@@ -0,0 +1,174 @@
+// Package fold implements the functionality for the "fold" Unix tool.
+package fold
+
+import (
+	"bufio"        // Provides buffered reading and writing.
+	"bytes"        // For finding the last blank of a line.
+	"flag"         // Used to parse command-line flags.
+	"fmt"          // For formatting error messages.
+	"io"           // Provides the reader and writer abstractions used for input and output.
+	"os"           // For interacting with the file system and OS I/O.
+	"unicode/utf8" // For counting columns in characters rather than bytes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// tabWidth is the distance between tab stops.
+const tabWidth = 8
+
+// options holds the parsed command-line flags.
+type options struct {
+	width  int  // -w: the maximum width of an output line.
+	spaces bool // -s: break after the last blank that fits, where there is one.
+	bytes  bool // -b: count bytes rather than columns.
+}
+
+// Run is the entry point for the fold functionality.
+// It wraps the lines of each file (or stdin) so none is wider than the
+// given width, and returns the exit status: 0 on success, 1 if any file
+// could not be read, or 2 for an invalid width.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "fold".
+	fset := flag.NewFlagSet("fold", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-w" flag to set the line width.
+	fset.IntVar(&opts.width, "w", 80, "use `WIDTH` columns instead of 80")
+	// Define the "-s" flag to break lines at blanks.
+	fset.BoolVar(&opts.spaces, "s", false, "break at spaces")
+	// Define the "-b" flag to count bytes instead of columns.
+	fset.BoolVar(&opts.bytes, "b", false, "count bytes rather than columns")
+	fset.Parse(args)
+
+	if opts.width < 1 {
+		cli.Fprintf(stderr, "fold", "invalid number of columns: '%d'", opts.width)
+		return cli.ExitUsage
+	}
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for _, file := range files {
+		if err := foldFile(out, file, stdin, &opts); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "fold", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "fold", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// foldFile wraps the lines of the named file, or of stdin for "-", to w.
+func foldFile(w *bufio.Writer, file string, stdin io.Reader, opts *options) error {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+		}
+		defer f.Close()
+		r = f
+	}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			newline := line[len(line)-1] == '\n'
+			if newline {
+				line = line[:len(line)-1]
+			}
+			foldLine(w, line, opts)
+			if newline {
+				w.WriteByte('\n')
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+		}
+	}
+}
+
+// foldLine writes line, which has no newline, to w, breaking it wherever
+// the next character would go past the width. With -s the break comes
+// after the last blank on the line so far, if there is one.
+func foldLine(w *bufio.Writer, line []byte, opts *options) {
+	var pending []byte // The part of the current output line not yet written.
+	col := 0
+	for len(line) > 0 {
+		size := 1
+		if !opts.bytes {
+			_, size = utf8.DecodeRune(line)
+		}
+		unit := line[:size]
+		// A character wider than the whole line still goes on a line of
+		// its own rather than breaking forever.
+		for len(pending) > 0 && advance(col, unit, opts) > opts.width {
+			if opts.spaces {
+				if i := bytes.LastIndexAny(pending, " \t"); i >= 0 {
+					w.Write(pending[:i+1])
+					w.WriteByte('\n')
+					pending = pending[i+1:]
+					col = columns(pending, opts)
+					continue
+				}
+			}
+			w.Write(pending)
+			w.WriteByte('\n')
+			pending, col = pending[:0], 0
+		}
+		pending = append(pending, unit...)
+		col = advance(col, unit, opts)
+		line = line[size:]
+	}
+	w.Write(pending)
+}
+
+// advance returns the column reached by printing unit, a single character
+// (or byte, with -b), at column col.
+func advance(col int, unit []byte, opts *options) int {
+	if opts.bytes {
+		return col + 1
+	}
+	switch unit[0] {
+	case '\t':
+		return (col/tabWidth + 1) * tabWidth
+	case '\b':
+		return max(col-1, 0)
+	case '\r':
+		return 0
+	default:
+		return col + 1
+	}
+}
+
+// columns returns the column reached by printing s from the start of a
+// line.
+func columns(s []byte, opts *options) int {
+	col := 0
+	for len(s) > 0 {
+		size := 1
+		if !opts.bytes {
+			_, size = utf8.DecodeRune(s)
+		}
+		col = advance(col, s[:size], opts)
+		s = s[size:]
+	}
+	return col
+}
@@ -0,0 +1,44 @@
+package fold
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{[]string{"-w", "5"}, "abcdefghijkl\n", "abcde\nfghij\nkl\n"},
+		{[]string{"-w", "5"}, "abcde\nfg", "abcde\nfg"},
+		{[]string{"-w", "10", "-s"}, "the quick brown fox jumps\n", "the quick \nbrown fox \njumps\n"},
+		{[]string{"-w", "4", "-s"}, "abcdefgh ij\n", "abcd\nefgh\n ij\n"},
+		{[]string{"-w", "10"}, "ab\tcdefgh\n", "ab\tcd\nefgh\n"},
+		{[]string{"-w", "10", "-b"}, "ab\tcdefghijk\n", "ab\tcdefghi\njk\n"},
+		{[]string{"-w", "3"}, "héllo\n", "hél\nlo\n"},
+		{[]string{"-w", "3", "-b"}, "héllo\n", "hé\nllo\n"},
+		{nil, strings.Repeat("x", 85) + "\n", strings.Repeat("x", 80) + "\nxxxxx\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, strings.NewReader(tt.input), &stdout, &stderr); status != 0 {
+			t.Fatalf("fold %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("fold %v: Expected %q but got %q", tt.args, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestInvalidWidth(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-w", "0"}, strings.NewReader(""), &stdout, &stderr); status != 2 {
+		t.Errorf("Expected status 2 but got %d", status)
+	}
+	if expected := "fold: invalid number of columns: '0'\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
@@ -0,0 +1,238 @@
+// Package grep implements the functionality for the "grep" Unix tool.
+package grep
+
+import (
+	"bufio"         // Provides buffered reading and writing.
+	"bytes"         // For detecting binary data.
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For formatted output of prefixes and counts.
+	"io"            // Provides the reader and writer abstractions used for input and output.
+	"io/fs"         // For directory walking.
+	"os"            // For interacting with the file system and OS I/O.
+	"path/filepath" // For walking directory trees and matching --include.
+	"regexp"        // For compiling and matching the pattern.
+	"strings"       // For trimming line endings.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Exit statuses, following POSIX grep.
+const (
+	exitMatch   = 0 // At least one line was selected.
+	exitNoMatch = 1 // No lines were selected.
+	exitTrouble = 2 // An error occurred.
+)
+
+// stdinLabel names standard input in prefixes and diagnostics.
+const stdinLabel = "(standard input)"
+
+// binaryCheckSize is how much of a file is inspected for NUL bytes when
+// deciding whether a recursive search should skip it as binary.
+const binaryCheckSize = 8192
+
+// options holds the parsed command-line flags.
+type options struct {
+	ignoreCase bool   // -i: match without regard to case.
+	invert     bool   // -v: select the lines that do not match.
+	lineNumber bool   // -n: prefix each line with its line number.
+	count      bool   // -c: print only the number of selected lines.
+	recursive  bool   // -r: search directories recursively.
+	include    string // --include: in recursive searches, only search files whose names match.
+	withName   bool   // Prefix output with the file name; set for several files or -r.
+}
+
+// Run is the entry point for the grep functionality.
+// It prints the lines of each file (or stdin, or with -r the files below
+// each directory) that match a regular expression and returns the exit status: 0 if any line was selected, 1
+// if none was, or 2 if an error occurred.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "grep".
+	fset := flag.NewFlagSet("grep", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-i" flag to ignore case distinctions.
+	fset.BoolVar(&opts.ignoreCase, "i", false, "ignore case distinctions in patterns and data")
+	// Define the "-v" flag to select non-matching lines.
+	fset.BoolVar(&opts.invert, "v", false, "select non-matching lines")
+	// Define the "-n" flag to print line numbers.
+	fset.BoolVar(&opts.lineNumber, "n", false, "print line number with output lines")
+	// Define the "-c" flag to print only a count of selected lines.
+	fset.BoolVar(&opts.count, "c", false, "print only a count of selected lines per file")
+	// Define the "-r" flag to search directory trees.
+	fset.BoolVar(&opts.recursive, "r", false, "search directories recursively, skipping binary files")
+	// Define the "--include" flag to limit recursive searches to some files.
+	fset.StringVar(&opts.include, "include", "", "search only files whose base name matches `GLOB`")
+	fset.Parse(args)
+
+	if fset.NArg() == 0 {
+		cli.Fprintf(stderr, "grep", "missing pattern")
+		return exitTrouble
+	}
+	pattern := fset.Arg(0)
+	if opts.ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		cli.Fprintf(stderr, "grep", "invalid pattern: %v", err)
+		return exitTrouble
+	}
+	if _, err := filepath.Match(opts.include, ""); err != nil {
+		cli.Fprintf(stderr, "grep", "invalid --include pattern '%s': %v", opts.include, err)
+		return exitTrouble
+	}
+
+	// Search standard input when no files are given, or with -r the
+	// current directory.
+	files := fset.Args()[1:]
+	if len(files) == 0 {
+		files = []string{"-"}
+		if opts.recursive {
+			files = []string{"."}
+		}
+	}
+	opts.withName = len(files) > 1 || opts.recursive
+
+	out := bufio.NewWriter(stdout)
+	status := exitNoMatch
+	trouble := false
+	for _, file := range files {
+		if opts.recursive && file != "-" {
+			matched, ok := searchTree(out, re, file, &opts, stderr)
+			if matched {
+				status = exitMatch
+			}
+			trouble = trouble || !ok
+			continue
+		}
+		matched, err := searchFile(out, re, file, stdin, &opts)
+		if err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "grep", "%v", err)
+			trouble = true
+		}
+		if matched {
+			status = exitMatch
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "grep", "write error: %v", err)
+		return exitTrouble
+	}
+	// Errors take precedence over whether anything matched.
+	if trouble {
+		return exitTrouble
+	}
+	return status
+}
+
+// searchFile searches the named file, or stdin for "-", reporting whether
+// any line was selected.
+func searchFile(w *bufio.Writer, re *regexp.Regexp, file string, stdin io.Reader, opts *options) (bool, error) {
+	if file == "-" {
+		return search(w, re, stdin, stdinLabel, opts)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return false, fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		return false, fmt.Errorf("%s: Is a directory", file)
+	}
+	// Recursive searches pass over binary files, recognized by a NUL byte
+	// near their start.
+	reader := bufio.NewReaderSize(f, binaryCheckSize)
+	if opts.recursive {
+		head, _ := reader.Peek(binaryCheckSize)
+		if bytes.IndexByte(head, 0) >= 0 {
+			return false, nil
+		}
+	}
+	matched, err := search(w, re, reader, file, opts)
+	if err != nil {
+		return matched, fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	return matched, nil
+}
+
+// searchTree searches every regular file below root, which may also be
+// a single file, prefixing matches with their paths as reached from root
+// (so relative roots give relative paths). Files
+// not matching --include are passed over, as are other kinds of entries
+// such as symbolic links. It reports whether any line was selected and
+// whether the whole tree could be searched.
+func searchTree(w *bufio.Writer, re *regexp.Regexp, root string, opts *options, stderr io.Writer) (matched, ok bool) {
+	ok = true
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Report the unreadable entry and carry on with the rest.
+			w.Flush()
+			cli.Fprintf(stderr, "grep", "%s: %v", path, cli.Unwrap(err))
+			ok = false
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if opts.include != "" {
+			if match, _ := filepath.Match(opts.include, d.Name()); !match {
+				return nil
+			}
+		}
+		found, err := searchFile(w, re, path, nil, opts)
+		if err != nil {
+			w.Flush()
+			cli.Fprintf(stderr, "grep", "%v", err)
+			ok = false
+		}
+		matched = matched || found
+		return nil
+	})
+	return matched, ok
+}
+
+// search prints the lines of r selected by re, or with -c their number,
+// prefixed as the options require; name is the prefix used for r. It
+// reports whether any line was selected.
+func search(w *bufio.Writer, re *regexp.Regexp, r io.Reader, name string, opts *options) (bool, error) {
+	reader := bufio.NewReader(r)
+	var selected int64
+	for lineNo := int64(1); ; lineNo++ {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			// Match the line without its terminator, so '$' anchors at its end.
+			text := strings.TrimSuffix(line, "\n")
+			if re.MatchString(text) != opts.invert {
+				selected++
+				if !opts.count {
+					if opts.withName {
+						fmt.Fprintf(w, "%s:", name)
+					}
+					if opts.lineNumber {
+						fmt.Fprintf(w, "%d:", lineNo)
+					}
+					w.WriteString(text + "\n")
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return selected > 0, err
+		}
+	}
+	if opts.count {
+		if opts.withName {
+			fmt.Fprintf(w, "%s:", name)
+		}
+		fmt.Fprintf(w, "%d\n", selected)
+	}
+	return selected > 0, nil
+}
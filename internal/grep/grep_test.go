@@ -0,0 +1,112 @@
+package grep
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+const poem = "The cat sat\non the mat\nA DOG barked\nthe end"
+
+func TestFlags(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		status   int
+	}{
+		{[]string{"at"}, "The cat sat\non the mat\n", 0},
+		{[]string{"^the"}, "the end\n", 0},
+		{[]string{"-i", "^the"}, "The cat sat\nthe end\n", 0},
+		{[]string{"-i", "dog"}, "A DOG barked\n", 0},
+		{[]string{"-v", "at"}, "A DOG barked\nthe end\n", 0},
+		{[]string{"-n", "the"}, "2:on the mat\n4:the end\n", 0},
+		{[]string{"-c", "at"}, "2\n", 0},
+		{[]string{"-c", "-v", "."}, "0\n", 1},
+		{[]string{"zebra"}, "", 1},
+		{[]string{"end$"}, "the end\n", 0},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, strings.NewReader(poem), &stdout, &stderr)
+		if stdout.String() != tt.expected || status != tt.status {
+			t.Errorf("grep %v: Expected %q (%d) but got %q (%d)", tt.args, tt.expected, tt.status, stdout.String(), status)
+		}
+	}
+}
+
+func TestMultipleFiles(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "apple\nbanana\n")
+	b := testutil.TempFile(t, "b.txt", "cherry\napricot\n")
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-n", "^a", a, b}, nil, &stdout, &stderr)
+	expected := a + ":1:apple\n" + b + ":2:apricot\n"
+	if stdout.String() != expected || status != 0 {
+		t.Errorf("Expected %q (0) but got %q (%d)", expected, stdout.String(), status)
+	}
+
+	stdout.Reset()
+	run([]string{"-c", "an", a, b}, nil, &stdout, &stderr)
+	if expected := a + ":1\n" + b + ":0\n"; stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "match\n")
+	missing := filepath.Join(t.TempDir(), "missing")
+	tests := []struct {
+		args   []string
+		stderr string
+	}{
+		{nil, "grep: missing pattern\n"},
+		{[]string{"("}, "grep: invalid pattern: error parsing regexp: missing closing ): `(`\n"},
+		{[]string{"match", a, missing}, "grep: " + missing + ": no such file or directory\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, strings.NewReader(""), &stdout, &stderr)
+		if stderr.String() != tt.stderr || status != 2 {
+			t.Errorf("grep %v: Expected %q (2) but got %q (%d)", tt.args, tt.stderr, stderr.String(), status)
+		}
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"a.go":          "package a\n// TODO: fix\n",
+		"notes.txt":     "nothing here\nTODO later\n",
+		"sub/b.go":      "func b() {} // TODO\n",
+		"sub/deep/c.go": "no match\n",
+		"sub/bin.go":    "TODO\x00binary\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write fixture: %v", err)
+		}
+	}
+	t.Chdir(root)
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-r", "TODO"}, "a.go:// TODO: fix\nnotes.txt:TODO later\n" + filepath.FromSlash("sub/b.go") + ":func b() {} // TODO\n"},
+		{[]string{"-r", "-n", "--include", "*.go", "TODO", "."}, "a.go:2:// TODO: fix\n" + filepath.FromSlash("sub/b.go") + ":1:func b() {} // TODO\n"},
+		{[]string{"-r", "-c", "TODO", "sub"}, filepath.FromSlash("sub/b.go") + ":1\n" + filepath.FromSlash("sub/deep/c.go") + ":0\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, nil, &stdout, &stderr)
+		if stdout.String() != tt.expected || status != 0 {
+			t.Errorf("grep %v: Expected %q (0) but got %q (%d) %s", tt.args, tt.expected, stdout.String(), status, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package head implements the functionality for the "head" Unix tool.
+package head
+
+import (
+	"bufio" // Provides buffered reading and writing.
+	"flag"  // Used to parse command-line flags.
+	"fmt"   // For formatting error messages.
+	"io"    // Provides the reader and writer abstractions used for input and output.
+	"os"    // For interacting with the file system and OS I/O.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// stdinLabel names standard input in headers and diagnostics.
+const stdinLabel = "standard input"
+
+// options holds the parsed command-line flags.
+type options struct {
+	lines int64 // -n: number of lines to print.
+	bytes int64 // -c: number of bytes to print; negative when counting lines.
+}
+
+// Run is the entry point for the head functionality.
+// It prints the first part of each file (or stdin) and returns the exit
+// status: 0 on success, or 1 if any input could not be read.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "head".
+	fset := flag.NewFlagSet("head", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-n" flag for the number of lines to print.
+	fset.Int64Var(&opts.lines, "n", 10, "print the first `N` lines")
+	// Define the "-c" flag for the number of bytes to print.
+	fset.Int64Var(&opts.bytes, "c", -1, "print the first `N` bytes")
+	fset.Parse(args)
+	if opts.lines < 0 {
+		cli.Fprintf(stderr, "head", "invalid number of lines: '%d'", opts.lines)
+		return cli.ExitUsage
+	}
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for i, file := range files {
+		// With several inputs, each one is introduced by a header.
+		if len(files) > 1 {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			name := file
+			if file == "-" {
+				name = stdinLabel
+			}
+			out.WriteString("==> " + name + " <==\n")
+		}
+		if err := headFile(out, file, stdin, &opts); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "head", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "head", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// headFile copies the start of the named file, or of stdin for "-", to w.
+func headFile(w *bufio.Writer, file string, stdin io.Reader, opts *options) error {
+	if file == "-" {
+		if err := head(w, stdin, opts); err != nil {
+			return fmt.Errorf("error reading '%s': %v", stdinLabel, err)
+		}
+		return nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("cannot open '%s' for reading: %v", file, cli.Unwrap(err))
+	}
+	defer f.Close()
+	if err := head(w, f, opts); err != nil {
+		return fmt.Errorf("error reading '%s': %v", file, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// head copies the first opts.bytes bytes of r to w, or, when no byte
+// count was given, its first opts.lines lines. Reading stops as soon as
+// the limit is reached, so only as much input as needed is consumed.
+func head(w *bufio.Writer, r io.Reader, opts *options) error {
+	if opts.bytes >= 0 {
+		_, err := io.CopyN(w, r, opts.bytes)
+		if err == io.EOF {
+			return nil // Shorter inputs are printed in full.
+		}
+		return err
+	}
+
+	reader := bufio.NewReader(r)
+	for n := int64(0); n < opts.lines; n++ {
+		// A final line without a newline is printed as it is.
+		line, err := reader.ReadSlice('\n')
+		// Copy long lines piecewise until their newline is found.
+		for err == bufio.ErrBufferFull {
+			w.Write(line)
+			line, err = reader.ReadSlice('\n')
+		}
+		w.Write(line)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package head
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+// runHead runs head with args over stdin and returns its output and status.
+func runHead(t *testing.T, stdin string, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), status
+}
+
+func TestLines(t *testing.T) {
+	input := strings.Repeat("line\n", 20)
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{nil, input, strings.Repeat("line\n", 10)},
+		{[]string{"-n", "3"}, input, "line\nline\nline\n"},
+		{[]string{"-n", "0"}, input, ""},
+		{[]string{"-n", "5"}, "a\nb", "a\nb"},
+	}
+	for _, tt := range tests {
+		got, status := runHead(t, tt.input, tt.args...)
+		if got != tt.expected || status != 0 {
+			t.Errorf("head %v: Expected %q (0) but got %q (%d)", tt.args, tt.expected, got, status)
+		}
+	}
+}
+
+func TestLongLine(t *testing.T) {
+	long := strings.Repeat("x", 10000) + "\n"
+	got, _ := runHead(t, long+"rest\n", "-n", "1")
+	if got != long {
+		t.Errorf("Expected a line of %d bytes but got %d bytes", len(long), len(got))
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		count    string
+		expected string
+	}{
+		{"4", "abc\n"},
+		{"0", ""},
+		{"100", "abc\ndef\n"},
+	}
+	for _, tt := range tests {
+		got, status := runHead(t, "abc\ndef\n", "-c", tt.count)
+		if got != tt.expected || status != 0 {
+			t.Errorf("head -c %s: Expected %q (0) but got %q (%d)", tt.count, tt.expected, got, status)
+		}
+	}
+}
+
+func TestMultipleFiles(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "1\n2\n3\n")
+	b := testutil.TempFile(t, "b.txt", "x\ny\n")
+	got, status := runHead(t, "in\n", "-n", "2", a, b, "-")
+	expected := "==> " + a + " <==\n1\n2\n\n==> " + b + " <==\nx\ny\n\n==> standard input <==\nin\n"
+	if got != expected || status != 0 {
+		t.Errorf("Expected %q (0) but got %q (%d)", expected, got, status)
+	}
+}
+
+func TestMissingFile(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "1\n")
+	var stdout, stderr bytes.Buffer
+	missing := filepath.Join(t.TempDir(), "missing")
+	status := run([]string{missing, a}, strings.NewReader(""), &stdout, &stderr)
+	if status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	if !strings.Contains(stdout.String(), "1\n") {
+		t.Errorf("Expected later files to be printed but got %q", stdout.String())
+	}
+	expected := "head: cannot open '" + missing + "' for reading: no such file or directory\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
@@ -0,0 +1,151 @@
+// Package id implements the functionality for the "id" Unix tool.
+package id
+
+import (
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatted I/O operations.
+	"io"      // Provides the writer abstraction used for output.
+	"os"      // For access to the standard streams.
+	"os/user" // To look up users, groups, and group memberships.
+	"strings" // Provides functions for string manipulation.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// options holds the parsed command-line flags for id.
+type options struct {
+	userOnly   bool // -u: print only the user ID.
+	groupOnly  bool // -g: print only the primary group ID.
+	groupsOnly bool // -G: print all group IDs.
+	names      bool // -n: print names instead of numbers (with -u, -g, or -G).
+}
+
+// Run is the entry point for the id functionality.
+// It returns 0 on success and 1 if the user cannot be found or the flags conflict.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "id".
+	fs := flag.NewFlagSet("id", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	var opts options
+	fs.BoolVar(&opts.userOnly, "u", false, "print only the effective user ID")
+	fs.BoolVar(&opts.groupOnly, "g", false, "print only the effective group ID")
+	fs.BoolVar(&opts.groupsOnly, "G", false, "print all group IDs")
+	fs.BoolVar(&opts.names, "n", false, "print a name instead of a number, for -u, -g, or -G")
+	fs.Parse(args)
+
+	// Validate the flag combination the same way coreutils does.
+	selected := 0
+	for _, set := range []bool{opts.userOnly, opts.groupOnly, opts.groupsOnly} {
+		if set {
+			selected++
+		}
+	}
+	if selected > 1 {
+		cli.Fprintf(stderr, "id", "cannot print \"only\" of more than one choice")
+		return cli.ExitUsage
+	}
+	if opts.names && selected == 0 {
+		cli.Fprintf(stderr, "id", "cannot print only names or real IDs in default format")
+		return cli.ExitUsage
+	}
+	if fs.NArg() > 1 {
+		cli.Fprintf(stderr, "id", "extra operand '%s'", fs.Arg(1))
+		return cli.ExitUsage
+	}
+
+	// Resolve the user to report on: the named one or the current user.
+	var (
+		u   *user.User
+		err error
+	)
+	if fs.NArg() == 1 {
+		u, err = lookup(fs.Arg(0))
+		if err != nil {
+			cli.Fprintf(stderr, "id", "'%s': no such user", fs.Arg(0))
+			return 1
+		}
+	} else {
+		u, err = user.Current()
+		if err != nil {
+			cli.Fprintf(stderr, "id", "cannot find current user: %v", err)
+			return 1
+		}
+	}
+
+	groups := groupIDs(u)
+
+	switch {
+	case opts.userOnly:
+		fmt.Fprintln(stdout, pick(opts.names, u.Uid, u.Username))
+	case opts.groupOnly:
+		fmt.Fprintln(stdout, pick(opts.names, u.Gid, groupName(u.Gid)))
+	case opts.groupsOnly:
+		fields := make([]string, len(groups))
+		for i, gid := range groups {
+			fields[i] = pick(opts.names, gid, groupName(gid))
+		}
+		fmt.Fprintln(stdout, strings.Join(fields, " "))
+	default:
+		fmt.Fprintln(stdout, format(u, groups))
+	}
+	return 0
+}
+
+// lookup finds a user by name, falling back to a numeric user ID.
+func lookup(name string) (*user.User, error) {
+	if u, err := user.Lookup(name); err == nil {
+		return u, nil
+	}
+	return user.LookupId(name)
+}
+
+// groupIDs returns the user's group IDs with the primary group first and
+// without duplicates. If memberships cannot be determined, only the
+// primary group is returned.
+func groupIDs(u *user.User) []string {
+	ids := []string{u.Gid}
+	all, err := u.GroupIds()
+	if err != nil {
+		return ids
+	}
+	for _, gid := range all {
+		if gid != u.Gid {
+			ids = append(ids, gid)
+		}
+	}
+	return ids
+}
+
+// groupName resolves a group ID to its name, or returns the ID unchanged.
+func groupName(gid string) string {
+	if g, err := user.LookupGroupId(gid); err == nil {
+		return g.Name
+	}
+	return gid
+}
+
+// pick returns name when names are requested and id otherwise.
+func pick(names bool, id, name string) string {
+	if names {
+		return name
+	}
+	return id
+}
+
+// format renders the default "uid=... gid=... groups=..." line.
+func format(u *user.User, groups []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "uid=%s(%s) gid=%s(%s) groups=", u.Uid, u.Username, u.Gid, groupName(u.Gid))
+	for i, gid := range groups {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s(%s)", gid, groupName(gid))
+	}
+	return b.String()
+}
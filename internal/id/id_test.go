@@ -0,0 +1,70 @@
+package id
+
+import (
+	"bytes"
+	"os/user"
+	"strings"
+	"testing"
+)
+
+func TestRunDefaultFormat(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("Cannot determine current user: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+
+	prefix := "uid=" + current.Uid + "(" + current.Username + ") gid=" + current.Gid + "("
+	got := stdout.String()
+	if !strings.HasPrefix(got, prefix) {
+		t.Errorf("Expected output to start with %q but got %q", prefix, got)
+	}
+	if !strings.Contains(got, " groups="+current.Gid+"(") {
+		t.Errorf("Expected primary group first in groups list but got %q", got)
+	}
+}
+
+func TestRunUserName(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("Cannot determine current user: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-u", "-n"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+	expected := current.Username + "\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	stdout.Reset()
+	run([]string{"-u", current.Username}, &stdout, &stderr)
+	expected = current.Uid + "\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"no-such-user-xyz"}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for an unknown user but got %d", code)
+	}
+	expected := "id: 'no-such-user-xyz': no such user\n"
+	if got := stderr.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	// Invalid command lines are usage errors.
+	for _, args := range [][]string{{"-u", "-g"}, {"-n"}, {"root", "extra"}} {
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("id %q: expected exit code 2 but got %d", args, code)
+		}
+	}
+}
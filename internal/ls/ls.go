@@ -2,66 +2,660 @@
 package ls
 
 import (
-	"flag"    // For parsing command-line flags.
-	"fmt"     // For formatted I/O.
-	"os"      // For file system and OS interaction.
-	"os/user" // To lookup user and group information.
-	"strings"
-
-	// For manipulating file paths.
-	"sort" // For sorting directory entries.
-	// For string manipulation.
-	"syscall" // To access low-level system calls and file metadata.
-	"time"    // For handling time and date formatting.
-
-	"golang.org/x/term" // To retrieve terminal size.
+	"flag"          // For parsing command-line flags.
+	"fmt"           // For formatted I/O.
+	"io"            // For the writer abstraction used for output.
+	"os"            // For file system and OS interaction.
+	"os/user"       // To lookup user and group information.
+	"path/filepath" // For manipulating file paths.
+	"slices"        // For reversing operands and removing long-format columns.
+	"sort"          // For sorting directory entries.
+	"strconv"       // For formatting sizes.
+	"strings"       // For string manipulation.
+	"time"          // For handling time and date formatting.
+	"unicode/utf8"  // For measuring names in runes rather than bytes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"      // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo" // Platform-specific file metadata.
+	"golang.org/x/term"                                    // To retrieve terminal size.
+)
+
+// options holds the parsed command-line flags that control a listing.
+type options struct {
+	longFormat     bool        // -l: use a long listing format.
+	includeHidden  bool        // -a, -A: include entries whose names start with a dot.
+	includeDotDirs bool        // -a: also include the "." and ".." entries.
+	recursive      bool        // -R: list subdirectories recursively.
+	reverse        bool        // -r: reverse the sort order.
+	sortByTime     bool        // -t: sort by modification time, newest first.
+	changeTime     bool        // -c: use the status change time instead of mtime.
+	accessTime     bool        // -u: use the last access time instead of mtime.
+	sortBySize     bool        // -S: sort by file size, largest first.
+	sortByExt      bool        // -X: sort by extension, names without one first.
+	humanReadable  bool        // -h: print sizes like 1.0K and 2.3M in long format.
+	singleColumn   bool        // -1, or output is not a terminal: one entry per line.
+	commas         bool        // -m: fill lines with names separated by ", ".
+	width          int         // -w: line width in columns; negative means the terminal's.
+	ignorePatterns patternList // -I: shell patterns of names to leave out; repeatable.
+	ignoreBackups  bool        // -B: leave out names ending in '~'.
+	directory      bool        // -d: list directory operands themselves, not their contents.
+	inode          bool        // -i: print each entry's inode number.
+	classify       bool        // -F: append an indicator such as '/' or '*' to names.
+	slashDirs      bool        // -p: append '/' to directory names.
+	icons          bool        // Prefix names with Nerd Font icons; off with --no-icons or when piped.
+	color          bool        // --color: wrap names in ANSI colors according to their type.
+	numericIDs     bool        // -n: like -l, but print numeric user and group IDs.
+	noGroup        bool        // -G, -o: leave the group column out of long listings.
+	dereference    bool        // -L: show information for the targets of symlinks.
+	versionSort    bool        // -v: natural sort of numbers within names.
+	summary        *summary    // --summary: running totals for the footer; nil when off.
+	blockSize      int64       // --block-size, -k: unit in bytes for sizes and totals; 0 for the defaults.
+	blockSuffix    string      // Unit letter printed after sizes for --block-size=K and the like.
+	timeStyle      string      // --time-style: how long listings format timestamps.
+}
+
+// fileEntry pairs a directory entry with its file information, which is
+// fetched on first use and cached so sorting and printing share one call.
+type fileEntry struct {
+	os.DirEntry
+	path     string      // Path of the entry, used to follow symlinks.
+	follow   bool        // -L: report the target of a symlink instead of the link.
+	info     os.FileInfo // Cached result of DirEntry.Info.
+	err      error       // Cached error from DirEntry.Info.
+	derefErr error       // Why a followed symlink could not be resolved, if it could not.
+	fetched  bool        // Whether info and err have been filled in.
+}
+
+// Info returns the entry's file information, fetching it only once. With
+// follow set, a symlink reports its target's information, or its own if
+// the target cannot be reached.
+func (e *fileEntry) Info() (os.FileInfo, error) {
+	if !e.fetched {
+		e.info, e.err = e.DirEntry.Info()
+		if e.err == nil && e.follow && e.info.Mode()&os.ModeSymlink != 0 {
+			if target, err := os.Stat(e.path); err == nil {
+				e.info = target
+			} else {
+				e.derefErr = cli.Unwrap(err)
+			}
+		}
+		e.fetched = true
+	}
+	return e.info, e.err
+}
+
+// namedEntry is a synthetic directory entry that presents info under an
+// arbitrary name. It is used for "." and "..", which os.ReadDir never
+// returns but "ls -a" lists, and for file operands, which are shown by the
+// path given on the command line rather than their base name.
+type namedEntry struct {
+	name string      // The name to display, e.g. "." or "dir/file.txt".
+	info os.FileInfo // Metadata of the file the name refers to.
+}
+
+func (n namedEntry) Name() string               { return n.name }
+func (n namedEntry) IsDir() bool                { return n.info.IsDir() }
+func (n namedEntry) Type() os.FileMode          { return n.info.Mode().Type() }
+func (n namedEntry) Info() (os.FileInfo, error) { return n.info, nil }
+
+// Exit statuses, matching GNU ls.
+const (
+	exitOK      = 0 // Everything was listed.
+	exitMinor   = 1 // A directory below an operand could not be read.
+	exitTrouble = 2 // An operand could not be accessed.
 )
 
-// Run executes the ls command, handling both default and long format listings.
-func Run(args []string) {
+// Run executes the ls command, handling both default and long format
+// listings, and returns the process exit status.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
 	// Create a new FlagSet to handle command-line options for ls.
 	fs := flag.NewFlagSet("ls", flag.ExitOnError)
+	var opts options
 	// Define the `-l` flag for long format listing.
-	longFormat := fs.Bool("l", false, "Use a long listing format")
+	fs.BoolVar(&opts.longFormat, "l", false, "Use a long listing format")
+	// Define the `-a` flag to include entries starting with a dot.
+	all := fs.Bool("a", false, "Do not ignore entries starting with .")
+	// Define the `-A` flag to include dotfiles but not "." and "..".
+	almostAll := fs.Bool("A", false, "Do not list implied . and ..")
+	// Define the `-R` flag to descend into subdirectories.
+	fs.BoolVar(&opts.recursive, "R", false, "List subdirectories recursively")
+	// Define the `-r` flag to reverse whichever sort order is in effect.
+	fs.BoolVar(&opts.reverse, "r", false, "Reverse order while sorting")
+	// Define the `-t` flag to sort by modification time.
+	fs.BoolVar(&opts.sortByTime, "t", false, "Sort by modification time, newest first")
+	// Define the `-c` flag to use the status change time.
+	fs.BoolVar(&opts.changeTime, "c", false, "With -l, show ctime; with -t, sort by ctime")
+	// Define the `-u` flag to use the last access time.
+	fs.BoolVar(&opts.accessTime, "u", false, "With -l, show atime; with -t, sort by atime")
+	// Define the `-S` flag to sort by size.
+	fs.BoolVar(&opts.sortBySize, "S", false, "Sort by file size, largest first")
+	// Define the `-X` flag to sort by extension.
+	fs.BoolVar(&opts.sortByExt, "X", false, "Sort alphabetically by entry extension")
+	// Define the `-h` flag for human-readable sizes in long format.
+	fs.BoolVar(&opts.humanReadable, "h", false, "With -l, print sizes like 1.0K 234M 2.0G")
+	// Define the `-1` flag to list one entry per line.
+	oneColumn := fs.Bool("1", false, "List one file per line")
+	// Define the `-m` flag for a comma-separated listing.
+	fs.BoolVar(&opts.commas, "m", false, "Fill width with a comma separated list of entries")
+	// Define the `-w` flag to override the terminal width.
+	fs.IntVar(&opts.width, "w", -1, "Set output width to `COLS`; 0 means no limit")
+	// Define the `-C` flag to force columns even when not writing to a terminal.
+	columns := fs.Bool("C", false, "List entries by columns")
+	// Define the `-d` flag to list directories themselves.
+	fs.BoolVar(&opts.directory, "d", false, "List directories themselves, not their contents")
+	// Define the `-i` flag to show inode numbers.
+	fs.BoolVar(&opts.inode, "i", false, "Print the index number of each file")
+	// Define the `-F` flag to append file type indicators.
+	fs.BoolVar(&opts.classify, "F", false, "Append indicator (one of */=@|) to entries")
+	// Define the `-p` flag to mark directories with a slash.
+	fs.BoolVar(&opts.slashDirs, "p", false, "Append / indicator to directories")
+	// Define the repeatable `-I` flag to hide names matching a pattern.
+	fs.Var(&opts.ignorePatterns, "I", "Do not list entries matching shell `PATTERN` (repeatable)")
+	// Define the `-B` flag to hide editor backup files.
+	fs.BoolVar(&opts.ignoreBackups, "B", false, "Do not list implied entries ending with ~")
+	// Define the `-L` flag to follow symbolic links.
+	fs.BoolVar(&opts.dereference, "L", false, "Show information for the file a symbolic link references")
+	// Define the `-v` flag for natural sorting of numbers in names.
+	fs.BoolVar(&opts.versionSort, "v", false, "Natural sort of (version) numbers within names")
+	// Define the `--summary` flag to print totals after the listing.
+	withSummary := fs.Bool("summary", false, "Print the number of directories and files and their total size")
+	// Define the `--block-size` flag to scale sizes and totals.
+	blockSize := fs.String("block-size", "", "Scale sizes by `SIZE`, e.g. K, M or 4096")
+	// Define the `-k` flag as a shorthand for 1K blocks.
+	kibibytes := fs.Bool("k", false, "Like --block-size=1K")
+	// Define the `--ignore-case` flag, kept for compatibility since names
+	// already sort without regard to case.
+	fs.Bool("ignore-case", false, "Sort names case-insensitively (the default)")
+	// Define the `-n` flag for long format with numeric IDs.
+	fs.BoolVar(&opts.numericIDs, "n", false, "Like -l, but list numeric user and group IDs")
+	// Define the `-G` flag to omit the group column.
+	fs.BoolVar(&opts.noGroup, "G", false, "In a long listing, don't print group names")
+	// Define the `-o` flag for long format without the group column.
+	noGroupLong := fs.Bool("o", false, "Like -l, but do not list group information")
+	// Define the `--time-style` flag to choose the timestamp format.
+	fs.StringVar(&opts.timeStyle, "time-style", "default", "Time format: `STYLE` is default, iso, long-iso or full-iso")
+	// Define the `--no-icons` flag, and its `--plain` alias, to list bare names.
+	noIcons := fs.Bool("no-icons", false, "Do not prefix names with icons")
+	fs.BoolVar(noIcons, "plain", false, "Same as --no-icons")
+	// Define the `--color` flag to color names by file type.
+	color := fs.String("color", "auto", "Color names by type: `WHEN` is auto, always or never")
 	// Parse the provided arguments.
 	fs.Parse(args)
+	opts.includeHidden = *all || *almostAll
+	opts.longFormat = opts.longFormat || opts.numericIDs || *noGroupLong
+	if *withSummary {
+		opts.summary = &summary{}
+	}
+	opts.noGroup = opts.noGroup || *noGroupLong
+	opts.includeDotDirs = *all
+	// Like GNU ls, default to one entry per line when output is not a terminal.
+	opts.singleColumn = *oneColumn || (!*columns && !isTerminal(stdout))
+	// Icons need a Nerd Font, so only show them on a terminal.
+	opts.icons = !*noIcons && isTerminal(stdout)
+	// Colors follow --color, but NO_COLOR (https://no-color.org) always wins.
+	switch *color {
+	case "always":
+		opts.color = true
+	case "auto":
+		opts.color = isTerminal(stdout)
+	case "never":
+	default:
+		cli.Fprintf(stderr, "ls", "invalid argument '%s' for '--color'", *color)
+		return exitTrouble
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		opts.color = false
+	}
+	if *blockSize != "" {
+		var err error
+		if opts.blockSize, opts.blockSuffix, err = parseBlockSize(*blockSize); err != nil {
+			cli.Fprintf(stderr, "ls", "%v", err)
+			return exitTrouble
+		}
+	} else if *kibibytes {
+		opts.blockSize = 1024
+	}
+	if _, ok := timeStyles[opts.timeStyle]; !ok && opts.timeStyle != "default" {
+		cli.Fprintf(stderr, "ls", "invalid argument '%s' for '--time-style'", opts.timeStyle)
+		return exitTrouble
+	}
+
+	// List the current directory when no operands are given.
+	operands := fs.Args()
+	if len(operands) == 0 {
+		operands = []string{"."}
+	}
+
+	// Split the operands into files, which are listed together first, and
+	// directories, which are listed one after another.
+	status := exitOK
+	var files []os.DirEntry
+	var dirs []string
+	for _, operand := range operands {
+		info, err := os.Lstat(operand)
+		if err != nil {
+			cli.Fprintf(stderr, "ls", "cannot access '%s': %v", operand, cli.Unwrap(err))
+			status = exitTrouble
+			continue
+		}
+		// Like GNU ls, a symlink operand is followed with -L, and otherwise
+		// only if it points to a directory and neither -l, -d nor -F asks
+		// about the link itself.
+		follow := opts.dereference
+		if !follow && !opts.longFormat && !opts.directory && !opts.classify && info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Stat(operand)
+			follow = err == nil && target.IsDir()
+		}
+		entry := &fileEntry{DirEntry: namedEntry{name: operand, info: info}, path: operand, follow: follow}
+		info, _ = entry.Info()
+		// With -d, directories are listed like any other file.
+		if info.IsDir() && !opts.directory {
+			dirs = append(dirs, operand)
+		} else {
+			files = append(files, entry)
+		}
+	}
+
+	printed := false // Whether a section has been written yet.
+	if len(files) > 0 {
+		sortEntries(files, &opts)
+		printEntries(stdout, "", files, &opts, false)
+		printed = true
+	}
+
+	sort.Strings(dirs)
+	if opts.reverse {
+		slices.Reverse(dirs)
+	}
+	// Like GNU ls, name each directory when more than one operand is
+	// given, and always with -R.
+	header := opts.recursive || len(operands) > 1
+	for _, dir := range dirs {
+		if printed {
+			fmt.Fprintln(stdout) // Blank line between sections.
+		}
+		if !listDirectory(stdout, stderr, dir, &opts, header) && status == exitOK {
+			status = exitMinor
+		}
+		printed = true
+	}
 
-	// Set the target directory; default to the current directory.
-	dir := "."
-	if fs.NArg() > 0 {
-		dir = fs.Arg(0) // Use the first non-flag argument as the directory.
+	if opts.summary != nil {
+		fmt.Fprintf(stdout, "\n%s, %s, %s total\n",
+			plural(opts.summary.dirs, "directory", "directories"),
+			plural(opts.summary.files, "file", "files"),
+			fileinfo.HumanSize(opts.summary.bytes))
+	}
+	return status
+}
+
+// listDirectory prints the entries of dir and, with -R, the contents of
+// every subdirectory below it, depth-first. When header is set the listing
+// is preceded by a "dir:" line. Unreadable directories are reported on
+// stderr without stopping the rest of the walk; the result is false if
+// any directory could not be read.
+func listDirectory(stdout, stderr io.Writer, dir string, opts *options, header bool) bool {
+	if header {
+		fmt.Fprintf(stdout, "%s:\n", dir)
 	}
 
 	// Read all entries in the target directory.
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		// Report error if directory cannot be accessed.
-		fmt.Fprintf(os.Stderr, "ls: cannot access '%s': %v\n", dir, err)
-		return
+		cli.Fprintf(stderr, "ls", "cannot access '%s': %v", dir, cli.Unwrap(err))
+		return false
 	}
 
-	// Sort directory entries alphabetically by their name.
-	sort.Slice(entries, func(i, j int) bool {
-		return strings.ToLower(entries[i].Name()) < strings.ToLower(entries[j].Name())
-	})
+	// Wrap the entries so their file information is cached once fetched.
+	for i, entry := range entries {
+		entries[i] = &fileEntry{
+			DirEntry: entry,
+			path:     filepath.Join(dir, entry.Name()),
+			follow:   opts.dereference,
+		}
+	}
+
+	// Drop hidden entries, or add "." and "..", depending on the flags.
+	entries = filterEntries(dir, entries, opts)
+
+	// Sort directory entries according to the flags.
+	sortEntries(entries, opts)
+
+	// Print the entries, with a total line in long format.
+	printEntries(stdout, dir, entries, opts, true)
+
+	if !opts.recursive {
+		return true
+	}
+	ok := true
+	// Descend into real subdirectories (not symlinks, "." or "..") in sorted order.
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+		fmt.Fprintln(stdout) // Blank line between directory sections.
+		if !listDirectory(stdout, stderr, filepath.Join(dir, entry.Name()), opts, true) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// summary accumulates the totals printed by --summary.
+type summary struct {
+	dirs  int   // Directories listed.
+	files int   // Other entries listed.
+	bytes int64 // Combined size of all listed entries.
+}
+
+// add counts entries into the totals. The "." and ".." entries are
+// skipped, as are entries that cannot be examined.
+func (s *summary) add(entries []os.DirEntry) {
+	for _, entry := range entries {
+		if entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			s.dirs++
+		} else {
+			s.files++
+		}
+		s.bytes += info.Size()
+	}
+}
+
+// printEntries writes entries, which live in dir, in the layout selected
+// by opts. In long format a "total" line is printed first if total is set.
+func printEntries(w io.Writer, dir string, entries []os.DirEntry, opts *options, total bool) {
+	if opts.summary != nil {
+		opts.summary.add(entries)
+	}
+
+	// With -i, every entry is preceded by its aligned inode number.
+	prefixes := inodePrefixes(entries, opts)
 
 	// Depending on the flag, choose the output format.
-	if *longFormat {
+	if opts.longFormat {
 		// In long format, first print the total disk blocks used.
-		printTotalBlocks(entries)
-		// Then print detailed information for each entry.
-		for _, entry := range entries {
-			printDetailedEntry(entry)
+		if total {
+			printTotalBlocks(w, entries, opts)
 		}
-	} else {
+		// Then print detailed information for each entry.
+		printDetailedEntries(w, dir, entries, prefixes, opts)
+		return
+	}
+
+	// The short layouts show each decorated name after any prefix.
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = prefixes[i] + displayName(dir, entry, opts)
+	}
+	// Lay out within the width given by -w, or else the terminal's.
+	width := opts.width
+	if width < 0 {
+		width = terminalWidth()
+	}
+	switch {
+	case opts.commas:
+		// Print a comma-separated stream, as for -m.
+		printCommaSeparated(w, names, width)
+	case opts.singleColumn || width == 0:
+		// Print one entry per line, as for -1, non-terminal output or -w 0.
+		printSingleColumn(w, names)
+	default:
 		// Otherwise, print entries in a multi-column layout.
-		printMultiColumn(entries)
+		printMultiColumn(w, names, width)
+	}
+}
+
+// inodePrefixes returns the text printed before each entry: with -i, its
+// inode number right-aligned to the widest one and followed by a space,
+// or "?" if the entry cannot be examined; otherwise empty strings.
+func inodePrefixes(entries []os.DirEntry, opts *options) []string {
+	prefixes := make([]string, len(entries))
+	if !opts.inode {
+		return prefixes
+	}
+
+	width := 0
+	for i, entry := range entries {
+		prefixes[i] = "?"
+		if info, err := entry.Info(); err == nil {
+			prefixes[i] = strconv.FormatUint(fileinfo.Inode(info), 10)
+		}
+		width = max(width, len(prefixes[i]))
 	}
+	for i, prefix := range prefixes {
+		prefixes[i] = strings.Repeat(" ", width-len(prefix)) + prefix + " "
+	}
+	return prefixes
+}
+
+// filterEntries applies the hidden-file rules to the entries of dir.
+// Entries for which ignored reports true are dropped, and the synthetic
+// "." and ".." entries are added when includeDotDirs is set.
+func filterEntries(dir string, entries []os.DirEntry, opts *options) []os.DirEntry {
+	visible := entries[:0]
+	for _, entry := range entries {
+		if !ignored(entry.Name(), opts) {
+			visible = append(visible, entry)
+		}
+	}
+	entries = visible
+
+	if opts.includeDotDirs {
+		// Add "." and "..", skipping either one if it cannot be examined.
+		for _, name := range []string{".", ".."} {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				entries = append(entries, namedEntry{name: name, info: info})
+			}
+		}
+	}
+	return entries
 }
 
-// printTotalBlocks calculates and prints the total number of disk blocks used by the files.
-func printTotalBlocks(entries []os.DirEntry) {
-	var totalBlocks int64
+// ignored reports whether a directory entry called name is left out of
+// listings: names starting with a dot unless includeHidden is set, backup
+// names ending in '~' with -B, and names matching any -I pattern.
+func ignored(name string, opts *options) bool {
+	if !opts.includeHidden && strings.HasPrefix(name, ".") {
+		return true
+	}
+	if opts.ignoreBackups && strings.HasSuffix(name, "~") {
+		return true
+	}
+	for _, pattern := range opts.ignorePatterns {
+		// Malformed patterns were rejected when the flags were parsed.
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// patternList is a flag.Value that collects the patterns of a repeatable
+// flag such as -I.
+type patternList []string
+
+func (p *patternList) String() string { return strings.Join(*p, ",") }
+
+// Set adds a pattern, rejecting any that filepath.Match cannot parse.
+func (p *patternList) Set(pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return err
+	}
+	*p = append(*p, pattern)
+	return nil
+}
+
+// sortEntries orders entries by name, by time (newest first) with -t, by
+// size (largest first) with -S, or by extension with -X. Names compare
+// without regard to case, falling back to byte order for names differing
+// only in case, and with -v runs of digits compare as numbers. With -a or
+// -A a leading dot is ignored, so ".bashrc" sorts next to "bash". Ties are
+// broken by name, and -r reverses the resulting order.
+func sortEntries(entries []os.DirEntry, opts *options) {
+	byName := func(a, b os.DirEntry) bool {
+		na, nb := a.Name(), b.Name()
+		if opts.includeHidden && na != "." && na != ".." && nb != "." && nb != ".." {
+			// Like GNU ls, collate dotfiles next to their undotted
+			// neighbours, keeping the full names as the tie-breaker.
+			if ka, kb := strings.TrimPrefix(na, "."), strings.TrimPrefix(nb, "."); ka != kb {
+				na, nb = ka, kb
+			}
+		}
+		if la, lb := strings.ToLower(na), strings.ToLower(nb); la != lb {
+			na, nb = la, lb
+		}
+		if opts.versionSort {
+			return naturalLess(na, nb)
+		}
+		return na < nb
+	}
+
+	less := byName
+	switch {
+	case opts.sortBySize:
+		less = func(a, b os.DirEntry) bool {
+			sa, sb := entrySize(a), entrySize(b)
+			if sa != sb {
+				return sa > sb
+			}
+			return byName(a, b)
+		}
+	case opts.sortByTime:
+		less = func(a, b os.DirEntry) bool {
+			ta, tb := entryTime(a, opts), entryTime(b, opts)
+			if !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+			return byName(a, b)
+		}
+	case opts.sortByExt:
+		less = func(a, b os.DirEntry) bool {
+			// Names without an extension have "" and so come first.
+			ea, eb := filepath.Ext(a.Name()), filepath.Ext(b.Name())
+			if ea != eb {
+				return ea < eb
+			}
+			return byName(a, b)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if opts.reverse {
+			return less(entries[j], entries[i])
+		}
+		return less(entries[i], entries[j])
+	})
+}
+
+// naturalLess reports whether a sorts before b in natural order, where
+// runs of digits compare by their numeric value, so "file2" sorts before
+// "file10". Everything else compares byte-wise. Numbers of equal value
+// written with more leading zeros sort later, and strings that compare
+// equal throughout fall back to a plain comparison.
+func naturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if !isDigit(a[i]) || !isDigit(b[j]) {
+			if a[i] != b[j] {
+				return a[i] < b[j]
+			}
+			i++
+			j++
+			continue
+		}
+
+		// Both strings have a run of digits here; take each in full.
+		si, sj := i, j
+		for i < len(a) && isDigit(a[i]) {
+			i++
+		}
+		for j < len(b) && isDigit(b[j]) {
+			j++
+		}
+		da := strings.TrimLeft(a[si:i], "0")
+		db := strings.TrimLeft(b[sj:j], "0")
+		// Without leading zeros, a longer run is a larger number.
+		if len(da) != len(db) {
+			return len(da) < len(db)
+		}
+		if da != db {
+			return da < db
+		}
+		if i-si != j-sj {
+			return i-si < j-sj
+		}
+	}
+	if len(a)-i != len(b)-j {
+		return len(a)-i < len(b)-j
+	}
+	return a < b
+}
+
+// isDigit reports whether c is an ASCII decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// entrySize returns the size of an entry, or 0 if it cannot be determined.
+func entrySize(entry os.DirEntry) int64 {
+	if info, err := entry.Info(); err == nil {
+		return info.Size()
+	}
+	return 0
+}
+
+// entryTime returns the timestamp of an entry selected by the flags: its
+// modification time, or its status change time with -c or access time
+// with -u. The zero time is returned if it cannot be determined.
+func entryTime(entry os.DirEntry, opts *options) time.Time {
+	if info, err := entry.Info(); err == nil {
+		return fileTime(info, opts)
+	}
+	return time.Time{}
+}
+
+// fileTime returns the timestamp of info selected by -c or -u, or its
+// modification time by default.
+func fileTime(info os.FileInfo, opts *options) time.Time {
+	switch {
+	case opts.changeTime:
+		return fileinfo.Ctime(info)
+	case opts.accessTime:
+		return fileinfo.Atime(info)
+	default:
+		return info.ModTime()
+	}
+}
+
+// printTotalBlocks prints the "total" line of a long listing: the disk
+// space used by the entries in 1K blocks, as reported by GNU ls, or in
+// units of --block-size.
+func printTotalBlocks(w io.Writer, entries []os.DirEntry, opts *options) {
+	unit := int64(1024)
+	if opts.blockSize > 0 {
+		unit = opts.blockSize
+	}
+	fmt.Fprintf(w, "total %d%s\n", totalBlocks(entries, unit), opts.blockSuffix)
+}
+
+// totalBlocks returns the disk usage of entries in blocks of unit bytes.
+// Like GNU ls, the 512-byte block counts are summed first and the sum is
+// then rounded up, so a lone 512-byte block still counts as one 1K block.
+func totalBlocks(entries []os.DirEntry, unit int64) int64 {
+	var total int64
 
 	// Iterate over each entry to accumulate its disk block usage.
 	for _, entry := range entries {
@@ -69,86 +663,375 @@ func printTotalBlocks(entries []os.DirEntry) {
 		if err != nil {
 			continue // Skip if file information cannot be obtained.
 		}
-		// Access the underlying system stat structure.
-		stat := info.Sys().(*syscall.Stat_t)
-		totalBlocks += stat.Blocks // Sum up the block count.
+		total += fileinfo.Blocks(info) // Sum up the 512-byte block count.
 	}
 
-	// Print the total blocks converted from 512-byte units to 1K blocks.
-	fmt.Printf("total %d\n", totalBlocks/2)
+	// Convert from 512-byte units to the requested unit, rounding up.
+	return ceilDiv(total*512, unit)
+}
+
+// ceilDiv returns n divided by d, rounded up.
+func ceilDiv(n, d int64) int64 {
+	return (n + d - 1) / d
 }
 
-// printDetailedEntry prints a detailed listing for a single file, similar to `ls -l`.
-func printDetailedEntry(entry os.DirEntry) {
+// parseBlockSize parses a --block-size argument: a number of bytes, a unit
+// suffix (K, M, G or T, for powers of 1024), or a number followed by a
+// suffix. A bare suffix is also appended to the printed sizes, as in GNU
+// ls, while "1K" and similar print plain numbers.
+func parseBlockSize(arg string) (size int64, suffix string, err error) {
+	digits := strings.TrimRight(arg, "KMGTkmgt")
+	unit := strings.ToUpper(arg[len(digits):])
+	size = 1
+	if digits != "" {
+		if size, err = strconv.ParseInt(digits, 10, 64); err != nil || size <= 0 {
+			return 0, "", fmt.Errorf("invalid --block-size argument '%s'", arg)
+		}
+	}
+	switch unit {
+	case "":
+		if digits == "" {
+			return 0, "", fmt.Errorf("invalid --block-size argument '%s'", arg)
+		}
+		return size, "", nil
+	case "K", "M", "G", "T":
+		// Each suffix is 1024 times the one before it.
+		size <<= 10 * (strings.Index("KMGT", unit) + 1)
+		if digits == "" {
+			return size, unit, nil
+		}
+		return size, "", nil
+	default:
+		return 0, "", fmt.Errorf("invalid --block-size argument '%s'", arg)
+	}
+}
+
+// field is one column of a long listing line.
+type field struct {
+	text  string // The text to print.
+	right bool   // Pad on the left, as for numbers, rather than on the right.
+}
+
+// printDetailedEntries prints a detailed listing of entries, which live
+// in dir, similar to `ls -l`, each line after its prefix. Every column but
+// the name is padded to its widest value among the entries, so that the
+// columns line up: numbers to the right and names to the left.
+func printDetailedEntries(w io.Writer, dir string, entries []os.DirEntry, prefixes []string, opts *options) {
+	rows := make([][]field, len(entries))
+	errs := make([]error, len(entries))
+	var widths []int
+	for i, entry := range entries {
+		rows[i], errs[i] = detailedFields(dir, entry, opts)
+		for j, f := range rows[i] {
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], displayWidth(f.text))
+		}
+	}
+
+	for i, entry := range entries {
+		fmt.Fprint(w, prefixes[i])
+		if errs[i] != nil {
+			fmt.Fprintf(w, "ls: error reading file info for %s: %v\n", entry.Name(), errs[i])
+			continue
+		}
+		columns := make([]string, len(rows[i]))
+		for j, f := range rows[i] {
+			pad := ""
+			if j < len(rows[i])-1 {
+				pad = strings.Repeat(" ", widths[j]-displayWidth(f.text))
+			}
+			if f.right {
+				columns[j] = pad + f.text
+			} else {
+				columns[j] = f.text + pad
+			}
+		}
+		fmt.Fprintln(w, strings.Join(columns, " "))
+	}
+}
+
+// detailedFields returns the columns of the long listing line for a single
+// file in dir. Symbolic links are followed by " -> target".
+func detailedFields(dir string, entry os.DirEntry, opts *options) ([]field, error) {
 	// Get file info for the entry.
 	info, err := entry.Info()
 	if err != nil {
-		fmt.Printf("ls: error reading file info for %s: %v\n", entry.Name(), err)
-		return
+		return nil, err
 	}
 
-	// Convert file info to a syscall.Stat_t to access additional metadata.
-	stat := info.Sys().(*syscall.Stat_t)
-
 	// Construct the permissions string.
-	perms := info.Mode().Perm().String()
-	if info.IsDir() {
-		perms = "d" + perms[1:] // Prefix with 'd' for directories.
-	} else {
-		perms = "-" + perms[1:] // Prefix with '-' for regular files.
+	perms := fileinfo.ModeString(info.Mode())
+
+	// Retrieve UID and GID as strings; with -n they are printed as is.
+	owner, group := "?", "?"
+	if uid, gid, ok := fileinfo.Owner(info); ok {
+		owner, group = strconv.Itoa(uid), strconv.Itoa(gid)
+	}
+	if !opts.numericIDs {
+		// Lookup the username associated with the UID.
+		if usr, err := user.LookupId(owner); err == nil {
+			owner = usr.Username // Otherwise fall back to the raw UID.
+		}
+		// Lookup the group name associated with the GID.
+		if grp, err := user.LookupGroupId(group); err == nil {
+			group = grp.Name // Otherwise fall back to the raw GID.
+		}
 	}
 
-	// Retrieve UID and GID as strings.
-	uid := fmt.Sprint(stat.Uid)
-	gid := fmt.Sprint(stat.Gid)
+	// Format the size as raw bytes, in human-readable units, or in blocks
+	// of --block-size rounded up.
+	size := strconv.FormatInt(info.Size(), 10)
+	if opts.humanReadable {
+		size = fileinfo.HumanSize(info.Size())
+	} else if opts.blockSize > 0 {
+		size = strconv.FormatInt(ceilDiv(info.Size(), opts.blockSize), 10) + opts.blockSuffix
+	}
 
-	// Lookup the username associated with the UID.
-	usr, err := user.LookupId(uid)
-	if err != nil {
-		usr = &user.User{Username: uid} // Fall back to raw UID if lookup fails.
+	// For symbolic links, show where the link points. A dangling link still
+	// has a readable target, so only an unreadable link drops the arrow.
+	// With -L only links whose target could not be reached get here, and
+	// they are marked with the reason.
+	name := displayName(dir, entry, opts)
+	if info.Mode()&os.ModeSymlink != 0 {
+		if target, err := os.Readlink(filepath.Join(dir, entry.Name())); err == nil {
+			name += " -> " + target
+		}
+		if fe, ok := entry.(*fileEntry); ok && fe.derefErr != nil {
+			name += fmt.Sprintf("  [cannot dereference: %v]", fe.derefErr)
+		}
 	}
 
-	// Lookup the group name associated with the GID.
-	grp, err := user.LookupGroupId(gid)
+	// Collect the columns, leaving out the group with -G or -o.
+	links := strconv.FormatUint(fileinfo.Links(info), 10)
+	when := formatTime(fileTime(info, opts), opts)
+	fields := []field{
+		{perms, false}, // Permissions string.
+		{links, true},  // Number of hard links.
+		{owner, false}, // Owner's username or UID.
+		{group, false}, // Group name or GID.
+		{size, true},   // File size.
+		{when, false},  // Formatted timestamp.
+		{name, false},  // File name with an icon and any link target.
+	}
+	if opts.noGroup {
+		fields = slices.Delete(fields, 3, 4)
+	}
+	return fields, nil
+}
+
+// displayName returns the name shown for entry, which lives in dir: its
+// name, prefixed with its icon unless icons are disabled and colored with
+// --color, followed with -F by the indicator for its type, or with -p by
+// a '/' for directories. Broken symbolic links get their own icon and
+// color. In long format symbolic links get no '@', since the "-> target"
+// suffix already marks them.
+func displayName(dir string, entry os.DirEntry, opts *options) string {
+	name := entry.Name()
+	if !opts.icons && !opts.classify && !opts.slashDirs && !opts.color {
+		return name
+	}
+	info, err := entry.Info()
+	broken := err == nil && info.Mode()&os.ModeSymlink != 0 &&
+		isBrokenSymlink(filepath.Join(dir, entry.Name()))
+	if opts.icons {
+		if broken {
+			name = brokenLinkIcon + name
+		} else {
+			name = getFileNameWithIcon(entry)
+		}
+	}
 	if err != nil {
-		grp = &user.Group{Name: gid} // Fall back to raw GID if lookup fails.
+		return name
 	}
+	if opts.color {
+		name = colorize(name, info, broken)
+	}
+	switch {
+	case opts.classify:
+		if opts.longFormat && info.Mode()&os.ModeSymlink != 0 {
+			return name
+		}
+		return name + indicator(info.Mode())
+	case opts.slashDirs && info.IsDir():
+		return name + "/"
+	default:
+		return name
+	}
+}
+
+// brokenLinkIcon marks symbolic links whose target does not exist.
+const brokenLinkIcon = "\U000f0338 "
 
-	// Format the modification time.
-	// Use a different format if the file is older than approximately 6 months.
-	timeFormat := "Jan _2 15:04"
-	if time.Since(info.ModTime()).Hours() > 6*30*24 {
-		timeFormat = "Jan _2 2006"
+// isBrokenSymlink reports whether path is a symbolic link whose target
+// cannot be reached.
+func isBrokenSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
 	}
+	_, err = os.Stat(path)
+	return err != nil
+}
+
+// ANSI color sequences used by --color, matching the GNU ls defaults.
+const (
+	colorDir     = "\x1b[01;34m"    // Bold blue for directories.
+	colorLink    = "\x1b[01;36m"    // Bold cyan for symbolic links.
+	colorOrphan  = "\x1b[40;31;01m" // Bold red on black for broken symbolic links.
+	colorExec    = "\x1b[01;32m"    // Bold green for executables.
+	colorArchive = "\x1b[01;31m"    // Bold red for archives.
+	colorReset   = "\x1b[0m"        // Restores the default color.
+)
 
-	// Print file details in a format similar to `ls -l`.
-	fmt.Printf("%s %d %s %s %4d %s %s\n",
-		perms,                             // Permissions string.
-		stat.Nlink,                        // Number of hard links.
-		usr.Username,                      // Owner's username.
-		grp.Name,                          // Group name.
-		info.Size(),                       // File size in bytes.
-		info.ModTime().Format(timeFormat), // Formatted modification time.
-		getFileNameWithIcon(entry),        // File name with an associated icon.
-	)
+// archiveExts lists the extensions colored as archives.
+var archiveExts = map[string]bool{
+	".tar": true, ".tgz": true, ".gz": true, ".bz2": true, ".xz": true,
+	".zst": true, ".zip": true, ".7z": true, ".rar": true, ".jar": true,
+}
+
+// colorize wraps name in the ANSI color for the file described by info,
+// or returns it unchanged if its type has no color. Broken symbolic links
+// are colored as orphans rather than as links.
+func colorize(name string, info os.FileInfo, broken bool) string {
+	var code string
+	switch mode := info.Mode(); {
+	case broken:
+		code = colorOrphan
+	case mode.IsDir():
+		code = colorDir
+	case mode&os.ModeSymlink != 0:
+		code = colorLink
+	case mode.IsRegular() && mode&0o111 != 0:
+		code = colorExec
+	case archiveExts[strings.ToLower(filepath.Ext(info.Name()))]:
+		code = colorArchive
+	default:
+		return name
+	}
+	return code + name + colorReset
 }
 
-// printMultiColumn arranges file entries into a multi-column layout based on the terminal width.
-func printMultiColumn(entries []os.DirEntry) {
+// indicator returns the -F suffix for mode: '/' for directories, '@' for
+// symbolic links, '|' for FIFOs, '=' for sockets, '*' for executable
+// regular files, and nothing for anything else.
+func indicator(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "/"
+	case mode&os.ModeSymlink != 0:
+		return "@"
+	case mode&os.ModeNamedPipe != 0:
+		return "|"
+	case mode&os.ModeSocket != 0:
+		return "="
+	case mode.IsRegular() && mode&0o111 != 0:
+		return "*"
+	default:
+		return ""
+	}
+}
+
+// timeStyles maps each --time-style other than "default" to its layout,
+// which, unlike the default, is used for recent and old files alike.
+var timeStyles = map[string]string{
+	"iso":      "2006-01-02",
+	"long-iso": "2006-01-02 15:04",
+	"full-iso": "2006-01-02 15:04:05.000000000 -0700",
+}
+
+// formatTime formats a timestamp for a long listing according to the
+// --time-style option. The default style shows the time of day for recent
+// files, and the year instead for files older than about 6 months.
+func formatTime(t time.Time, opts *options) string {
+	if layout, ok := timeStyles[opts.timeStyle]; ok {
+		return t.Format(layout)
+	}
+	if time.Since(t).Hours() > 6*30*24 {
+		return t.Format("Jan _2 2006")
+	}
+	return t.Format("Jan _2 15:04")
+}
+
+// printSingleColumn prints each name on a line of its own.
+func printSingleColumn(w io.Writer, names []string) {
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+}
+
+// displayWidth returns the number of terminal columns s occupies, counting
+// each rune (including Nerd Font icon glyphs) as a single column. ANSI
+// color sequences take up no space and are skipped.
+func displayWidth(s string) int {
+	width := 0
+	for len(s) > 0 {
+		if strings.HasPrefix(s, "\x1b[") {
+			// Skip to the end of the sequence, which is its final 'm'.
+			if end := strings.IndexByte(s, 'm'); end >= 0 {
+				s = s[end+1:]
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		width++
+	}
+	return width
+}
+
+// isTerminal reports whether w is a terminal device.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// terminalWidth returns the width of the terminal in columns, or 80 if
+// it cannot be determined.
+func terminalWidth() int {
 	// Attempt to get the terminal width.
-	width, _, err := term.GetSize(int(syscall.Stdin))
+	width, _, err := term.GetSize(int(os.Stdin.Fd()))
 	if err != nil || width < 20 {
 		width = 80 // Default to 80 columns if the terminal size is not available.
 	}
-	var names []string
-	maxLen := 0 // Track the longest filename length.
-	// Collect file names along with their icons.
-	for _, entry := range entries {
-		name := getFileNameWithIcon(entry)
-		names = append(names, name)
+	return width
+}
+
+// printCommaSeparated prints names separated by ", ", starting a new line
+// whenever the next name would not fit within width columns, like ls -m.
+// A width of 0 puts every name on a single line.
+func printCommaSeparated(w io.Writer, names []string, width int) {
+	pos := 0 // Display column at which the next character is written.
+	for i, name := range names {
+		n := displayWidth(name)
+		if i > 0 {
+			// Keep the comma on this line and wrap before the name if
+			// the separator and name would reach the last column.
+			if width == 0 || pos+2+n < width {
+				fmt.Fprint(w, ", ")
+				pos += 2
+			} else {
+				fmt.Fprint(w, ",\n")
+				pos = 0
+			}
+		}
+		fmt.Fprint(w, name)
+		pos += n
+	}
+	if len(names) > 0 {
+		fmt.Fprintln(w)
+	}
+}
 
-		if len(name) > maxLen {
-			maxLen = len(name) // Update max length for padding.
+// printMultiColumn arranges names into a multi-column layout that fits
+// within width columns.
+func printMultiColumn(w io.Writer, names []string, width int) {
+	maxLen := 0 // Track the longest name, in display columns.
+	for _, name := range names {
+		// Icons are multibyte glyphs, so measure runes rather than bytes.
+		if n := displayWidth(name); n > maxLen {
+			maxLen = n // Update max length for padding.
 		}
 	}
 
@@ -160,10 +1043,11 @@ func printMultiColumn(entries []os.DirEntry) {
 
 	// Loop through the names and print them in columns.
 	for i, name := range names {
-		fmt.Printf("%-*s", colWidth, name) // Left-align within the column width.
+		// Left-align within the column width, padding by display width.
+		fmt.Fprint(w, name, strings.Repeat(" ", colWidth-displayWidth(name)))
 		// Insert a newline when a row is complete or at the end of the list.
 		if (i+1)%cols == 0 || i == len(names)-1 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 	}
 }
@@ -0,0 +1,30 @@
+//go:build unix && !aix && !solaris
+
+package ls
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// This test has a file of its own since syscall.Mkfifo is missing on AIX
+// and Solaris.
+func TestLongFIFO(t *testing.T) {
+	dir := t.TempDir()
+	if err := syscall.Mkfifo(filepath.Join(dir, "pipe"), 0o644); err != nil {
+		t.Fatalf("Failed to create FIFO: %v", err)
+	}
+
+	out := runLs(t, "-l", dir)
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasSuffix(line, "pipe") {
+			if !strings.HasPrefix(line, "prw-r--r--") {
+				t.Errorf("Expected FIFO permissions %q but got %q", "prw-r--r--", line)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected a line for the FIFO but got %q", out)
+}
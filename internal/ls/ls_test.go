@@ -0,0 +1,918 @@
+package ls
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// makeTree creates the given files (and directories, for names ending in
+// "/") inside a fresh temp dir and returns its path.
+func makeTree(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatalf("Failed to create %s: %v", path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create parent of %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("Failed to create %s: %v", path, err)
+		}
+	}
+	return dir
+}
+
+// runLs runs ls with the given arguments and returns its standard output.
+func runLs(t *testing.T, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	run(args, &stdout, &stderr)
+	if stderr.Len() != 0 {
+		t.Fatalf("Unexpected stderr output: %q", stderr.String())
+	}
+	return stdout.String()
+}
+
+// longNames returns the last field of every entry line of a long listing.
+func longNames(out string) []string {
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if strings.HasPrefix(line, "total ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		names = append(names, fields[len(fields)-1])
+	}
+	return names
+}
+
+func TestHiddenByDefault(t *testing.T) {
+	dir := makeTree(t, ".bashrc", "visible.txt", ".config/")
+
+	out := runLs(t, dir)
+	if !strings.Contains(out, "visible.txt") {
+		t.Errorf("Expected visible.txt in %q", out)
+	}
+	if strings.Contains(out, ".bashrc") || strings.Contains(out, ".config") {
+		t.Errorf("Expected dotfiles to be hidden but got %q", out)
+	}
+}
+
+func TestShowAll(t *testing.T) {
+	dir := makeTree(t, ".bashrc", "visible.txt")
+
+	names := longNames(runLs(t, "-l", "-a", dir))
+	expected := []string{".", "..", ".bashrc", "visible.txt"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q but got %q", expected, names)
+	}
+}
+
+func TestAlmostAll(t *testing.T) {
+	dir := makeTree(t, ".bashrc", "visible.txt")
+
+	names := longNames(runLs(t, "-l", "-A", dir))
+	expected := []string{".bashrc", "visible.txt"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q but got %q", expected, names)
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	dir := makeTree(t, "top.txt", "a/inner.txt", "a/deep/leaf.txt", "b/")
+
+	out := runLs(t, "-R", dir)
+	headers := []string{
+		dir + ":\n",
+		"\n\n" + filepath.Join(dir, "a") + ":\n",
+		"\n\n" + filepath.Join(dir, "a", "deep") + ":\n",
+		"\n\n" + filepath.Join(dir, "b") + ":\n",
+	}
+	last := -1
+	for _, header := range headers {
+		i := strings.Index(out, header)
+		if i <= last {
+			t.Fatalf("Expected header %q after position %d in %q", header, last, out)
+		}
+		last = i
+	}
+
+	// Each file appears in the section of its own directory.
+	sections := strings.Split(out, "\n\n")
+	for i, name := range []string{"top.txt", "inner.txt", "leaf.txt"} {
+		if !strings.Contains(sections[i], name) {
+			t.Errorf("Expected %s in section %q", name, sections[i])
+		}
+	}
+}
+
+func TestRecursivePermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("Permission checks do not apply to root")
+	}
+	dir := makeTree(t, "locked/secret.txt", "open/file.txt")
+	locked := filepath.Join(dir, "locked")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+	defer os.Chmod(locked, 0o755)
+
+	var stdout, stderr bytes.Buffer
+	run([]string{"-R", dir}, &stdout, &stderr)
+	if !strings.Contains(stderr.String(), "cannot access '"+locked+"'") {
+		t.Errorf("Expected an error for the locked directory but got %q", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "file.txt") {
+		t.Errorf("Expected listing to continue past the error but got %q", stdout.String())
+	}
+}
+
+// setModTimes gives each named file in dir the matching modification time.
+func setModTimes(t *testing.T, dir string, times map[string]time.Time) {
+	t.Helper()
+	for name, mtime := range times {
+		if err := os.Chtimes(filepath.Join(dir, name), mtime, mtime); err != nil {
+			t.Fatalf("Failed to set times on %s: %v", name, err)
+		}
+	}
+}
+
+func TestSortByTime(t *testing.T) {
+	dir := makeTree(t, "old", "middle", "new", "tie-a", "tie-b")
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	setModTimes(t, dir, map[string]time.Time{
+		"old":    base,
+		"middle": base.Add(time.Hour),
+		"new":    base.Add(2 * time.Hour),
+		"tie-a":  base.Add(30 * time.Minute),
+		"tie-b":  base.Add(30 * time.Minute),
+	})
+
+	names := longNames(runLs(t, "-l", "-t", dir))
+	expected := []string{"new", "middle", "tie-a", "tie-b", "old"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q but got %q", expected, names)
+	}
+
+	names = longNames(runLs(t, "-l", "-t", "-r", dir))
+	expected = []string{"old", "tie-b", "tie-a", "middle", "new"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q with -r but got %q", expected, names)
+	}
+}
+
+func TestSortBySize(t *testing.T) {
+	dir := makeTree(t)
+	sizes := map[string]int{"small": 10, "large": 3000, "medium": 500, "same-a": 100, "same-b": 100}
+	for name, size := range sizes {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	names := longNames(runLs(t, "-l", "-S", dir))
+	expected := []string{"large", "medium", "same-a", "same-b", "small"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q but got %q", expected, names)
+	}
+
+	names = longNames(runLs(t, "-l", "-S", "-r", dir))
+	expected = []string{"small", "same-b", "same-a", "medium", "large"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q with -r but got %q", expected, names)
+	}
+}
+
+func TestLongHumanReadable(t *testing.T) {
+	dir := makeTree(t)
+	if err := os.WriteFile(filepath.Join(dir, "data"), make([]byte, 1536), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if out := runLs(t, "-l", "-h", dir); !strings.Contains(out, " 1.5K ") {
+		t.Errorf("Expected a 1.5K size in %q", out)
+	}
+	if out := runLs(t, "-l", dir); !strings.Contains(out, " 1536 ") {
+		t.Errorf("Expected a 1536 byte size in %q", out)
+	}
+}
+
+func TestSingleColumn(t *testing.T) {
+	dir := makeTree(t, "alpha", "beta", "gamma/")
+
+	for _, args := range [][]string{{"-1", dir}, {dir}} {
+		lines := strings.Split(strings.TrimSuffix(runLs(t, args...), "\n"), "\n")
+		expected := []string{"alpha", "beta", "gamma"}
+		if len(lines) != len(expected) {
+			t.Fatalf("ls %v: expected %d lines but got %q", args, len(expected), lines)
+		}
+		for i, line := range lines {
+			if line != expected[i] {
+				t.Errorf("ls %v: expected line %d to be %q but got %q", args, i, expected[i], line)
+			}
+		}
+	}
+
+	// -C forces columns even though the output is not a terminal.
+	out := runLs(t, "-C", dir)
+	if n := strings.Count(out, "\n"); n != 1 {
+		t.Errorf("Expected a single row with -C but got %q", out)
+	}
+}
+
+func TestMultiColumnAlignment(t *testing.T) {
+	// The .md icon is a 4-byte glyph while the default icon is 3 bytes.
+	dir := makeTree(t, "a.md", "b.zz", "c.md", "d.zz")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+
+	var buf bytes.Buffer
+	printEntries(&buf, dir, entries, &options{icons: true, width: 80}, false)
+	out := strings.TrimSuffix(buf.String(), "\n")
+	if strings.Contains(out, "\n") {
+		t.Fatalf("Expected a single row but got %q", out)
+	}
+
+	// Every name is 6 display columns wide with its icon, so each column
+	// should be exactly 8 columns including the padding.
+	runes := []rune(out)
+	for i, name := range []string{"a.md", "b.zz", "c.md", "d.zz"} {
+		start := i*8 + 2 // Skip the icon and its trailing space.
+		if got := string(runes[start : start+len(name)]); got != name {
+			t.Errorf("Expected %q at column %d but got %q in %q", name, start, got, out)
+		}
+	}
+}
+
+func TestLongSymlink(t *testing.T) {
+	dir := makeTree(t, "target.txt")
+	if err := os.Symlink("target.txt", filepath.Join(dir, "link")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	if err := os.Symlink("missing", filepath.Join(dir, "broken")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	out := runLs(t, "-l", dir)
+	for _, want := range []string{"link -> target.txt", "broken -> missing"} {
+		found := false
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasSuffix(line, want) {
+				found = true
+				if !strings.HasPrefix(line, "l") {
+					t.Errorf("Expected symlink type 'l' but got %q", line)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Expected %q in output but got %q", want, out)
+		}
+	}
+}
+
+func TestLongSpecialBits(t *testing.T) {
+	dir := makeTree(t, "sticky/", "setuid")
+	if err := os.Chmod(filepath.Join(dir, "sticky"), os.ModeDir|os.ModeSticky|0o777); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+	if err := os.Chmod(filepath.Join(dir, "setuid"), os.ModeSetuid|0o755); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+
+	out := runLs(t, "-l", dir)
+	for _, want := range []string{"drwxrwxrwt", "-rwsr-xr-x"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output but got %q", want, out)
+		}
+	}
+}
+
+func TestLongFormat(t *testing.T) {
+	dir := makeTree(t, "file.txt", "sub/")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	out := runLs(t, "-l", dir)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "total ") {
+		t.Fatalf("Expected a total line and two entries but got %q", out)
+	}
+	fields := strings.Fields(lines[1])
+	if fields[0] != "-rw-r--r--" || fields[1] != "1" || fields[4] != "5" {
+		t.Errorf("Expected mode, link count and size %q but got %q", "-rw-r--r-- 1 5", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "d") {
+		t.Errorf("Expected a directory entry but got %q", lines[2])
+	}
+}
+
+// fakeInfo is an os.FileInfo with no underlying stat data, so block
+// counts are derived from its size.
+type fakeInfo struct {
+	name string
+	size int64
+}
+
+func (f fakeInfo) Name() string       { return f.name }
+func (f fakeInfo) Size() int64        { return f.size }
+func (f fakeInfo) Mode() os.FileMode  { return 0o644 }
+func (f fakeInfo) ModTime() time.Time { return time.Time{} }
+func (f fakeInfo) IsDir() bool        { return false }
+func (f fakeInfo) Sys() any           { return nil }
+
+func TestTotalBlocks(t *testing.T) {
+	tests := []struct {
+		sizes []int64
+		want  int64
+	}{
+		{nil, 0},
+		{[]int64{1}, 1},         // One 512-byte block rounds up to 1K.
+		{[]int64{1, 513}, 2},    // 1 + 2 blocks = 1.5K, rounded up.
+		{[]int64{4096, 100}, 5}, // 8 + 1 blocks = 4.5K, rounded up.
+		{[]int64{1024, 1024}, 2},
+	}
+	for _, tt := range tests {
+		var entries []os.DirEntry
+		for i, size := range tt.sizes {
+			entries = append(entries, namedEntry{name: string(rune('a' + i)), info: fakeInfo{size: size}})
+		}
+		if got := totalBlocks(entries, 1024); got != tt.want {
+			t.Errorf("Expected total %d for sizes %v but got %d", tt.want, tt.sizes, got)
+		}
+	}
+}
+
+func TestIgnoreCase(t *testing.T) {
+	dir := makeTree(t, "banana", "Apple", "cherry", "Date")
+
+	// Names compare without regard to case, with or without --ignore-case.
+	expected := []string{"Apple", "banana", "cherry", "Date"}
+	for _, args := range [][]string{{"-l", dir}, {"-l", "--ignore-case", dir}} {
+		names := longNames(runLs(t, args...))
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Errorf("ls %q: expected %q but got %q", args, expected, names)
+		}
+	}
+}
+
+func TestMultipleOperands(t *testing.T) {
+	root := makeTree(t, "one/a.txt", "two/b.txt", "file.txt")
+	one := filepath.Join(root, "one")
+	two := filepath.Join(root, "two")
+	file := filepath.Join(root, "file.txt")
+
+	out := runLs(t, "-1", two, file, one)
+	expected := strings.Join([]string{
+		file,
+		"",
+		one + ":",
+		"a.txt",
+		"",
+		two + ":",
+		"b.txt",
+	}, "\n") + "\n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+}
+
+func TestMissingOperand(t *testing.T) {
+	dir := makeTree(t, "a.txt")
+	missing := filepath.Join(dir, "missing")
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-1", missing, dir}, &stdout, &stderr)
+	if status != exitTrouble {
+		t.Errorf("Expected exit status %d but got %d", exitTrouble, status)
+	}
+	expected := "ls: cannot access '" + missing + "': no such file or directory\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "a.txt") {
+		t.Errorf("Expected the remaining operand to be listed but got %q", stdout.String())
+	}
+}
+
+func TestListDirectoryItself(t *testing.T) {
+	t.Chdir(makeTree(t, "a.txt", "sub/b.txt"))
+
+	names := longNames(runLs(t, "-l", "-d", "."))
+	if strings.Join(names, " ") != "." {
+		t.Errorf("Expected %q but got %q", []string{"."}, names)
+	}
+
+	names = longNames(runLs(t, "-l", "-d", "sub", "a.txt"))
+	expected := []string{"a.txt", "sub"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q but got %q", expected, names)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	dir := makeTree(t, "sub/", "plain.txt", "run.sh")
+	if err := os.Chmod(filepath.Join(dir, "run.sh"), 0o755); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+	if err := os.Symlink("plain.txt", filepath.Join(dir, "link")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	names := longNames(runLs(t, "-1", "-F", dir))
+	expected := []string{"link@", "plain.txt", "run.sh*", "sub/"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q but got %q", expected, names)
+	}
+
+	// In long format the arrow marks symlinks, so they get no '@'.
+	out := runLs(t, "-l", "-F", dir)
+	if !strings.Contains(out, "link -> plain.txt") || !strings.Contains(out, "sub/\n") {
+		t.Errorf("Expected classified long output but got %q", out)
+	}
+}
+
+func TestNoIcons(t *testing.T) {
+	dir := makeTree(t, "main.go", "notes.md", "sub/")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+
+	// Icons are on only when requested and writing to a terminal.
+	var buf bytes.Buffer
+	printEntries(&buf, dir, entries, &options{icons: true, singleColumn: true}, false)
+	if buf.String() == "main.go\nnotes.md\nsub\n" {
+		t.Errorf("Expected icons in %q", buf.String())
+	}
+
+	for _, args := range [][]string{{"-1", "--no-icons"}, {"-l", "--plain"}, {"-C"}} {
+		out := runLs(t, append(args, dir)...)
+		for _, r := range out {
+			if r >= utf8.RuneSelf {
+				t.Errorf("Expected plain ASCII output for %q but got %q", args, out)
+				break
+			}
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	dir := makeTree(t, "sub/", "plain.txt", "backup.tar")
+	t.Setenv("NO_COLOR", "")
+
+	out := runLs(t, "-1", "--color=always", dir)
+	for _, want := range []string{colorArchive + "backup.tar" + colorReset, "plain.txt", colorDir + "sub" + colorReset} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %q", want, out)
+		}
+	}
+
+	if out := runLs(t, "-1", "--color=never", dir); strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected no color with --color=never but got %q", out)
+	}
+	if out := runLs(t, "-1", "--color=auto", dir); strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected no color with --color=auto when not a terminal but got %q", out)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if out := runLs(t, "-1", "--color=always", dir); strings.Contains(out, "\x1b[") {
+		t.Errorf("Expected NO_COLOR to disable color but got %q", out)
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"abc":                          3,
+		" file":                       6,
+		colorDir + "sub" + colorReset:  3,
+		colorExec + " a" + colorReset: 3,
+	}
+	for s, want := range tests {
+		if got := displayWidth(s); got != want {
+			t.Errorf("Expected width %d for %q but got %d", want, s, got)
+		}
+	}
+}
+
+func TestTimeStyle(t *testing.T) {
+	stamp := time.Date(2021, time.March, 4, 5, 6, 7, 890000000, time.FixedZone("", 2*60*60))
+	tests := map[string]string{
+		"default":  "Mar  4 2021",
+		"iso":      "2021-03-04",
+		"long-iso": "2021-03-04 05:06",
+		"full-iso": "2021-03-04 05:06:07.890000000 +0200",
+	}
+	for style, want := range tests {
+		if got := formatTime(stamp, &options{timeStyle: style}); got != want {
+			t.Errorf("Expected %q for --time-style=%s but got %q", want, style, got)
+		}
+	}
+
+	// Recent files show the time of day in the default style.
+	recent := time.Now().Add(-time.Hour)
+	if got, want := formatTime(recent, &options{timeStyle: "default"}), recent.Format("Jan _2 15:04"); got != want {
+		t.Errorf("Expected %q for a recent file but got %q", want, got)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-l", "--time-style=bogus", t.TempDir()}, &stdout, &stderr); status != exitTrouble {
+		t.Errorf("Expected exit status %d for an invalid style but got %d", exitTrouble, status)
+	}
+}
+
+func TestCommaSeparated(t *testing.T) {
+	var names []string
+	for i := 0; i < 30; i++ {
+		names = append(names, fmt.Sprintf("f%02d", i))
+	}
+
+	var buf bytes.Buffer
+	printCommaSeparated(&buf, names, 40)
+	expected := "f00, f01, f02, f03, f04, f05, f06, f07,\n" +
+		"f08, f09, f10, f11, f12, f13, f14, f15,\n" +
+		"f16, f17, f18, f19, f20, f21, f22, f23,\n" +
+		"f24, f25, f26, f27, f28, f29\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, buf.String())
+	}
+
+	// -m takes precedence over the single-column default for pipes.
+	dir := makeTree(t, "a", "b", "c")
+	if out := runLs(t, "-m", dir); out != "a, b, c\n" {
+		t.Errorf("Expected %q but got %q", "a, b, c\n", out)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	dir := makeTree(t, "aaa", "bbb", "ccc", "ddd", "eee")
+
+	// Each column is 5 wide, so a width of 12 fits two per row.
+	out := runLs(t, "-C", "-w", "12", dir)
+	expected := "aaa  bbb  \nccc  ddd  \neee  \n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+
+	// A width of 0 falls back to one entry per line.
+	out = runLs(t, "-C", "-w", "0", dir)
+	expected = "aaa\nbbb\nccc\nddd\neee\n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+
+	out = runLs(t, "-m", "-w", "12", dir)
+	expected = "aaa, bbb,\nccc, ddd,\neee\n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+}
+
+func TestSlashDirs(t *testing.T) {
+	dir := makeTree(t, "alpha/", "beta.txt", "gamma/", "run.sh")
+	if err := os.Chmod(filepath.Join(dir, "run.sh"), 0o755); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+
+	// Only directories are marked, and the slash counts towards the
+	// column width: with 10-wide columns, two fit in 20.
+	out := runLs(t, "-C", "-w", "20", "-p", dir)
+	expected := "alpha/    beta.txt  \ngamma/    run.sh    \n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+}
+
+func TestIgnorePatterns(t *testing.T) {
+	dir := makeTree(t, "main.c", "main.o", "util.o", "notes.txt", "build/")
+
+	out := runLs(t, "-1", "-I", "*.o", "-I", "build", dir)
+	expected := "main.c\nnotes.txt\n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+}
+
+func TestIgnoreBackups(t *testing.T) {
+	dir := makeTree(t, "file.txt", "file.txt~", ".hidden~")
+
+	if out := runLs(t, "-1", dir); out != "file.txt\nfile.txt~\n" {
+		t.Errorf("Expected the backup to be listed but got %q", out)
+	}
+	if out := runLs(t, "-1", "-B", dir); out != "file.txt\n" {
+		t.Errorf("Expected the backup to be hidden but got %q", out)
+	}
+	// -B applies on top of -A, which would otherwise show .hidden~.
+	if out := runLs(t, "-1", "-A", "-B", dir); out != "file.txt\n" {
+		t.Errorf("Expected only %q but got %q", "file.txt", out)
+	}
+}
+
+func TestDereference(t *testing.T) {
+	dir := makeTree(t)
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), bytes.Repeat([]byte("x"), 12345), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Symlink("big.txt", filepath.Join(dir, "link")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	if err := os.Symlink("missing", filepath.Join(dir, "broken")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	// linkLine returns the fields of the long listing line naming name.
+	linkLine := func(out, name string) []string {
+		for _, line := range strings.Split(out, "\n") {
+			fields := strings.Fields(line)
+			if slices.Contains(fields, name) {
+				return fields
+			}
+		}
+		t.Fatalf("Expected a line for %s in %q", name, out)
+		return nil
+	}
+
+	// By default the link itself is described.
+	fields := linkLine(runLs(t, "-l", dir), "link")
+	if !strings.HasPrefix(fields[0], "l") || fields[4] == "12345" {
+		t.Errorf("Expected the link itself but got %q", fields)
+	}
+
+	// With -L the target's type, permissions and size are shown instead.
+	out := runLs(t, "-l", "-L", dir)
+	fields = linkLine(out, "link")
+	if fields[0] != "-rw-------" || fields[4] != "12345" {
+		t.Errorf("Expected the target's mode and size but got %q", fields)
+	}
+
+	// A broken link falls back to the link itself, with a note.
+	if !strings.Contains(out, "broken -> missing  [cannot dereference: no such file or directory]") {
+		t.Errorf("Expected a note for the broken link in %q", out)
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	sorted := []string{"a", "file1", "file01", "file2", "file10", "file10a", "file10b", "fileA", "img2x9", "img10x1"}
+	for i := range sorted {
+		for j := range sorted {
+			if got, want := naturalLess(sorted[i], sorted[j]), i < j; got != want {
+				t.Errorf("Expected naturalLess(%q, %q) to be %v but got %v", sorted[i], sorted[j], want, got)
+			}
+		}
+	}
+}
+
+func TestVersionSort(t *testing.T) {
+	dir := makeTree(t, "img10", "img2", "img20", "img1")
+
+	if out := runLs(t, "-1", dir); out != "img1\nimg10\nimg2\nimg20\n" {
+		t.Errorf("Expected byte-wise order but got %q", out)
+	}
+	if out := runLs(t, "-1", "-v", dir); out != "img1\nimg2\nimg10\nimg20\n" {
+		t.Errorf("Expected numeric order but got %q", out)
+	}
+}
+
+func TestSortByExtension(t *testing.T) {
+	dir := makeTree(t, "b.txt", "a.md", "main.go", "Makefile", "a.txt", "util.go")
+
+	out := runLs(t, "-1", "-X", dir)
+	expected := "Makefile\nmain.go\nutil.go\na.md\na.txt\nb.txt\n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+
+	out = runLs(t, "-1", "-X", "-r", dir)
+	expected = "b.txt\na.txt\na.md\nutil.go\nmain.go\nMakefile\n"
+	if out != expected {
+		t.Errorf("Expected %q with -r but got %q", expected, out)
+	}
+}
+
+func TestBrokenSymlink(t *testing.T) {
+	dir := makeTree(t, "target.txt")
+	if err := os.Symlink("target.txt", filepath.Join(dir, "good")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	if err := os.Symlink("missing", filepath.Join(dir, "bad")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	if isBrokenSymlink(filepath.Join(dir, "good")) || isBrokenSymlink(filepath.Join(dir, "target.txt")) {
+		t.Errorf("Expected only the dangling link to be reported as broken")
+	}
+	if !isBrokenSymlink(filepath.Join(dir, "bad")) {
+		t.Errorf("Expected the dangling link to be reported as broken")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	for _, args := range [][]string{{"-1"}, {"-l"}} {
+		out := runLs(t, append(args, "--color=always", dir)...)
+		if !strings.Contains(out, colorOrphan+"bad"+colorReset) {
+			t.Errorf("ls %v: expected the broken link to be colored as an orphan in %q", args, out)
+		}
+		if !strings.Contains(out, colorLink+"good"+colorReset) {
+			t.Errorf("ls %v: expected the working link to be colored as a link in %q", args, out)
+		}
+	}
+
+	// With icons, the broken link gets its own icon.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	var buf bytes.Buffer
+	printEntries(&buf, dir, entries, &options{icons: true, singleColumn: true}, false)
+	if !strings.HasPrefix(buf.String(), brokenLinkIcon+"bad\n") {
+		t.Errorf("Expected the broken link icon in %q", buf.String())
+	}
+}
+
+func TestSummary(t *testing.T) {
+	dir := makeTree(t, "sub/", "other/", "sub/nested.txt")
+	for name, size := range map[string]int{"a.bin": 1024, "b.bin": 2048, "sub/nested.txt": 100} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Only the listed entries count; directory sizes vary by file system,
+	// so compare the counts and check the size prefix separately.
+	out := runLs(t, "-1", "--summary", dir)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "2 directories, 2 files, ") || !strings.HasSuffix(last, " total") {
+		t.Errorf("Expected a summary of 2 directories and 2 files but got %q", last)
+	}
+
+	// With -R the nested file is counted too.
+	out = runLs(t, "-1", "-R", "--summary", dir)
+	if !strings.Contains(out, "\n2 directories, 3 files, ") {
+		t.Errorf("Expected a recursive summary but got %q", out)
+	}
+
+	// For plain files the total size is exact.
+	out = runLs(t, "--summary", filepath.Join(dir, "a.bin"), filepath.Join(dir, "b.bin"))
+	if !strings.HasSuffix(out, "\n0 directories, 2 files, 3.0K total\n") {
+		t.Errorf("Expected a 3.0K total but got %q", out)
+	}
+}
+
+func TestSortIgnoresLeadingDot(t *testing.T) {
+	dir := makeTree(t, "bash", ".bashrc", "config", ".cache", "zsh", ".profile", "profile")
+
+	out := runLs(t, "-1", "-A", dir)
+	expected := "bash\n.bashrc\n.cache\nconfig\n.profile\nprofile\nzsh\n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+
+	// "." and ".." still lead the listing with -a.
+	names := longNames(runLs(t, "-l", "-a", dir))
+	if names[0] != "." || names[1] != ".." || names[2] != "bash" {
+		t.Errorf("Expected . and .. first but got %q", names)
+	}
+}
+
+func TestParseBlockSize(t *testing.T) {
+	tests := []struct {
+		arg    string
+		size   int64
+		suffix string
+	}{
+		{"K", 1024, "K"},
+		{"M", 1 << 20, "M"},
+		{"g", 1 << 30, "G"},
+		{"1K", 1024, ""},
+		{"4K", 4096, ""},
+		{"512", 512, ""},
+	}
+	for _, tt := range tests {
+		size, suffix, err := parseBlockSize(tt.arg)
+		if err != nil || size != tt.size || suffix != tt.suffix {
+			t.Errorf("Expected %d %q for %q but got %d %q (%v)", tt.size, tt.suffix, tt.arg, size, suffix, err)
+		}
+	}
+	for _, arg := range []string{"", "0", "-1", "X", "1.5K"} {
+		if _, _, err := parseBlockSize(arg); err == nil {
+			t.Errorf("Expected an error for %q", arg)
+		}
+	}
+}
+
+func TestBlockSize(t *testing.T) {
+	dir := makeTree(t)
+	for name, size := range map[string]int64{"small": 100, "medium": 3000, "large": 3 << 20} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// sizes returns the total line and the size column of a long listing.
+	sizes := func(args ...string) (string, []string) {
+		lines := strings.Split(strings.TrimSpace(runLs(t, append(args, "-S", dir)...)), "\n")
+		var column []string
+		for _, line := range lines[1:] {
+			column = append(column, strings.Fields(line)[4])
+		}
+		return lines[0], column
+	}
+
+	_, column := sizes("-l", "--block-size=K")
+	if strings.Join(column, " ") != "3072K 3K 1K" {
+		t.Errorf("Expected sizes in K but got %q", column)
+	}
+	_, column = sizes("-l", "--block-size=M")
+	if strings.Join(column, " ") != "3M 1M 1M" {
+		t.Errorf("Expected sizes in M but got %q", column)
+	}
+	_, column = sizes("-l", "-k")
+	if strings.Join(column, " ") != "3072 3 1" {
+		t.Errorf("Expected sizes in 1K units but got %q", column)
+	}
+
+	// The total scales with the block size as well.
+	total1K, _ := sizes("-l")
+	totalK, _ := sizes("-l", "--block-size=K")
+	if totalK != total1K+"K" {
+		t.Errorf("Expected %q to match %q with a K suffix", totalK, total1K)
+	}
+	var blocks1K int64
+	fmt.Sscanf(total1K, "total %d", &blocks1K)
+	totalM, _ := sizes("-l", "--block-size=M")
+	if expected := fmt.Sprintf("total %dM", ceilDiv(blocks1K, 1024)); totalM != expected {
+		t.Errorf("Expected %q but got %q", expected, totalM)
+	}
+}
+
+func TestSymlinkOperands(t *testing.T) {
+	dir := makeTree(t, "sub/inner.txt", "target.txt")
+	t.Chdir(dir)
+	for target, link := range map[string]string{"target.txt": "link", "missing": "dangling", "sub": "dirlink"} {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+
+	// With -l the links themselves are listed, even a dangling one.
+	out := runLs(t, "-l", "link", "dangling")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two lines but got %q", out)
+	}
+	for i, suffix := range []string{"dangling -> missing", "link -> target.txt"} {
+		if !strings.HasPrefix(lines[i], "l") || !strings.HasSuffix(lines[i], suffix) {
+			t.Errorf("Expected a link line ending in %q but got %q", suffix, lines[i])
+		}
+	}
+
+	// Without -l a link to a directory is listed as the directory, but
+	// -d lists the link.
+	if out := runLs(t, "-1", "dirlink"); out != "inner.txt\n" {
+		t.Errorf("Expected the directory's contents but got %q", out)
+	}
+	if names := longNames(runLs(t, "-l", "-d", "dirlink")); strings.Join(names, " ") != "sub" {
+		t.Errorf("Expected the link itself but got %q", names)
+	}
+
+	// With -L the target is described instead.
+	if out := runLs(t, "-l", "-L", "link"); !strings.HasPrefix(out, "-") || strings.Contains(out, "->") {
+		t.Errorf("Expected the target's details but got %q", out)
+	}
+}
+
+func TestLongAlignment(t *testing.T) {
+	dir := makeTree(t, "a", "b", "c/")
+	if err := os.WriteFile(filepath.Join(dir, "b"), bytes.Repeat([]byte("x"), 123456), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	for _, args := range [][]string{{"-l"}, {"-l", "-i"}, {"-o"}} {
+		out := runLs(t, append(args, dir)...)
+		lines := strings.Split(strings.TrimSpace(out), "\n")[1:]
+		// The names all have one letter, so aligned lines are equally long,
+		// and the sizes end in the same column.
+		sizeEnd := strings.Index(lines[1], "123456") + len("123456")
+		for _, line := range lines {
+			if len(line) != len(lines[0]) || line[sizeEnd] != ' ' || line[sizeEnd-1] == ' ' {
+				t.Errorf("ls %v: expected aligned columns but got %q", args, out)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+//go:build unix
+
+package ls
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInode(t *testing.T) {
+	dir := makeTree(t, "a.txt", "sub/")
+
+	for _, args := range [][]string{{"-i", "-1", dir}, {"-i", "-l", dir}} {
+		out := runLs(t, args...)
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			if strings.HasPrefix(line, "total ") {
+				continue
+			}
+			fields := strings.Fields(line)
+			name := fields[len(fields)-1]
+			var stat syscall.Stat_t
+			if err := syscall.Lstat(filepath.Join(dir, name), &stat); err != nil {
+				t.Fatalf("Failed to stat %s: %v", name, err)
+			}
+			expected := strconv.FormatUint(uint64(stat.Ino), 10)
+			if fields[0] != expected || expected == "0" {
+				t.Errorf("Expected inode %s for %s but got %q", expected, name, line)
+			}
+		}
+	}
+}
+
+func TestNumericIDs(t *testing.T) {
+	dir := makeTree(t, "a.txt")
+	var stat syscall.Stat_t
+	if err := syscall.Stat(filepath.Join(dir, "a.txt"), &stat); err != nil {
+		t.Fatalf("Failed to stat: %v", err)
+	}
+
+	out := strings.TrimSpace(runLs(t, "-n", dir))
+	lines := strings.Split(out, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a total line and one entry but got %q", out)
+	}
+	fields := strings.Fields(lines[1])
+	uid := strconv.FormatUint(uint64(stat.Uid), 10)
+	gid := strconv.FormatUint(uint64(stat.Gid), 10)
+	if fields[2] != uid || fields[3] != gid {
+		t.Errorf("Expected owner %s and group %s but got %q", uid, gid, lines[1])
+	}
+}
+
+func TestNoGroup(t *testing.T) {
+	dir := makeTree(t, "a.txt")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("12345"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	for _, args := range [][]string{{"-n", "-G"}, {"-o", "-n"}} {
+		lines := strings.Split(strings.TrimSpace(runLs(t, append(args, dir)...)), "\n")
+		fields := strings.Fields(lines[len(lines)-1])
+		// Without the group, the size directly follows the owner.
+		expected := []string{"-rw-r--r--", "1", strconv.Itoa(os.Getuid()), "5"}
+		if len(fields) != 8 || strings.Join(fields[:4], " ") != strings.Join(expected, " ") {
+			t.Errorf("ls %v: expected columns %q but got %q", args, expected, fields)
+		}
+	}
+}
+
+func TestAccessAndChangeTime(t *testing.T) {
+	dir := makeTree(t, "old", "new")
+	past := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.Local)
+	recent := time.Date(2024, time.June, 1, 12, 30, 0, 0, time.Local)
+	// "old" was modified first but accessed last, and vice versa.
+	if err := os.Chtimes(filepath.Join(dir, "old"), recent, past); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+	if err := os.Chtimes(filepath.Join(dir, "new"), past, recent); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+
+	names := longNames(runLs(t, "-l", "-t", dir))
+	if strings.Join(names, " ") != "new old" {
+		t.Errorf("Expected mtime order %q but got %q", "new old", names)
+	}
+	names = longNames(runLs(t, "-l", "-t", "-u", dir))
+	if strings.Join(names, " ") != "old new" {
+		t.Errorf("Expected atime order %q but got %q", "old new", names)
+	}
+
+	// With -u the access time is displayed instead of the modification time.
+	out := runLs(t, "-l", "-u", "--time-style=long-iso", dir)
+	if !strings.Contains(out, "2024-06-01 12:30 old") || !strings.Contains(out, "2020-01-01 00:00 new") {
+		t.Errorf("Expected access times in %q", out)
+	}
+
+	// Changing the mode of "old" makes it the most recently changed file.
+	time.Sleep(10 * time.Millisecond)
+	if err := os.Chmod(filepath.Join(dir, "old"), 0o600); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+	names = longNames(runLs(t, "-l", "-t", "-c", dir))
+	if strings.Join(names, " ") != "old new" {
+		t.Errorf("Expected ctime order %q but got %q", "old new", names)
+	}
+	out = runLs(t, "-l", "-c", "--time-style=long-iso", dir)
+	if strings.Contains(out, "2024-06-01") || strings.Contains(out, "2020-01-01") {
+		t.Errorf("Expected change times rather than set times in %q", out)
+	}
+}
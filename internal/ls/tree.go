@@ -0,0 +1,158 @@
+package ls
+
+import (
+	"flag"          // For parsing command-line flags.
+	"fmt"           // For formatted I/O.
+	"io"            // For the writer abstraction used for output.
+	"os"            // For file system and OS interaction.
+	"path/filepath" // For manipulating file paths.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Connector strings drawn in front of each entry of a tree listing.
+const (
+	treeBranch = "├── " // Precedes an entry with siblings below it.
+	treeLast   = "└── " // Precedes the last entry of a directory.
+	treeVert   = "│   " // Continues the line of a branch with more entries.
+	treeBlank  = "    " // Indents below the last entry of a directory.
+)
+
+// treeCounts tallies what a tree listing has shown, for its summary line.
+type treeCounts struct {
+	dirs  int // Directories below the roots.
+	files int // Everything else.
+}
+
+// RunTree is the entry point for the "tree" functionality.
+// It prints each directory (the current one by default) as an indented
+// hierarchy and returns the exit status: 0 on success, 1 if a directory
+// below a root could not be read, or 2 if a root could not be accessed.
+func RunTree(args []string) int {
+	return runTree(args, os.Stdout, os.Stderr)
+}
+
+// runTree performs the actual work of RunTree using the provided streams.
+func runTree(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "tree".
+	fs := flag.NewFlagSet("tree", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	var opts options
+	// Define the `-a` flag to include entries starting with a dot.
+	fs.BoolVar(&opts.includeHidden, "a", false, "Do not ignore entries starting with .")
+	// Define the `-L` flag to limit how deep the tree descends.
+	depth := fs.Int("L", 0, "Descend at most `DEPTH` levels; 0 means no limit")
+	// Define the `--no-icons` flag to list bare names.
+	noIcons := fs.Bool("no-icons", false, "Do not prefix names with icons")
+	fs.Parse(args)
+	if *depth < 0 {
+		cli.Fprintf(stderr, "tree", "invalid level '%d', must be 0 or greater", *depth)
+		return exitTrouble
+	}
+	// As with ls, icons are only shown on a terminal.
+	opts.icons = !*noIcons && isTerminal(stdout)
+
+	// Draw the current directory when no roots are given.
+	roots := fs.Args()
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	status := exitOK
+	var counts treeCounts
+	for _, root := range roots {
+		if _, err := os.Stat(root); err != nil {
+			cli.Fprintf(stderr, "tree", "cannot access '%s': %v", root, cli.Unwrap(err))
+			status = exitTrouble
+			continue
+		}
+		entries, err := readTreeDir(root, &opts)
+		if err != nil {
+			fmt.Fprintf(stdout, "%s  [error opening dir]\n", root)
+			status = exitMinor
+			continue
+		}
+		fmt.Fprintln(stdout, root)
+		if !printTree(stdout, root, entries, "", 1, *depth, &opts, &counts) && status == exitOK {
+			status = exitMinor
+		}
+	}
+
+	fmt.Fprintf(stdout, "\n%s, %s\n",
+		plural(counts.dirs, "directory", "directories"),
+		plural(counts.files, "file", "files"))
+	return status
+}
+
+// readTreeDir returns the entries of dir that a tree listing shows, in
+// display order.
+func readTreeDir(dir string, opts *options) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	// Wrap the entries so their file information is cached once fetched.
+	for i, entry := range entries {
+		entries[i] = &fileEntry{DirEntry: entry}
+	}
+	entries = filterEntries(dir, entries, opts)
+	sortEntries(entries, opts)
+	return entries, nil
+}
+
+// printTree prints entries, the contents of dir at the given level of the
+// tree, each preceded by prefix and its connector, and recurses into
+// subdirectories until maxDepth (0 for no limit) is reached. Directories
+// that cannot be read are marked in place; the result is false if that
+// happened anywhere below dir.
+func printTree(w io.Writer, dir string, entries []os.DirEntry, prefix string, level, maxDepth int, opts *options, counts *treeCounts) bool {
+	ok := true
+	for i, entry := range entries {
+		// The last entry closes the branch, so nothing continues below it.
+		connector, indent := treeBranch, treeVert
+		if i == len(entries)-1 {
+			connector, indent = treeLast, treeBlank
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		name := displayName(dir, entry, opts)
+		if entry.Type()&os.ModeSymlink != 0 {
+			// Symbolic links show their target and are never followed.
+			if target, err := os.Readlink(path); err == nil {
+				name += " -> " + target
+			}
+		}
+
+		if !entry.IsDir() {
+			counts.files++
+			fmt.Fprintln(w, prefix+connector+name)
+			continue
+		}
+		counts.dirs++
+		if maxDepth > 0 && level >= maxDepth {
+			// Below the depth limit, directories are listed but not opened.
+			fmt.Fprintln(w, prefix+connector+name)
+			continue
+		}
+		// Read the directory first, so a failure can be noted on its line.
+		children, err := readTreeDir(path, opts)
+		if err != nil {
+			fmt.Fprintf(w, "%s%s%s  [error opening dir]\n", prefix, connector, name)
+			ok = false
+			continue
+		}
+		fmt.Fprintln(w, prefix+connector+name)
+		if !printTree(w, path, children, prefix+indent, level+1, maxDepth, opts, counts) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// plural formats n followed by the singular or plural form of a noun.
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
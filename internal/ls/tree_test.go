@@ -0,0 +1,69 @@
+package ls
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// runTreeCmd runs tree with the given arguments and returns its standard
+// output and exit status.
+func runTreeCmd(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	status := runTree(args, &stdout, &stderr)
+	if stderr.Len() != 0 {
+		t.Fatalf("Unexpected stderr output: %q", stderr.String())
+	}
+	return stdout.String(), status
+}
+
+func TestTree(t *testing.T) {
+	dir := makeTree(t, "a/b/c.txt", "a/d.txt", "e.txt", ".hidden")
+	t.Chdir(dir)
+
+	out, status := runTreeCmd(t)
+	expected := ".\n" +
+		"├── a\n" +
+		"│   ├── b\n" +
+		"│   │   └── c.txt\n" +
+		"│   └── d.txt\n" +
+		"└── e.txt\n" +
+		"\n2 directories, 3 files\n"
+	if out != expected || status != exitOK {
+		t.Errorf("Expected %q with status 0 but got %q with status %d", expected, out, status)
+	}
+
+	out, _ = runTreeCmd(t, "-L", "1", "-a")
+	expected = ".\n" +
+		"├── a\n" +
+		"├── e.txt\n" +
+		"└── .hidden\n" +
+		"\n1 directory, 2 files\n"
+	if out != expected {
+		t.Errorf("Expected %q but got %q", expected, out)
+	}
+}
+
+func TestTreePermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("Permission checks do not apply to root")
+	}
+	dir := makeTree(t, "locked/secret.txt", "open/file.txt")
+	locked := filepath.Join(dir, "locked")
+	if err := os.Chmod(locked, 0o000); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(locked, 0o755) })
+
+	out, status := runTreeCmd(t, dir)
+	expected := dir + "\n" +
+		"├── locked  [error opening dir]\n" +
+		"└── open\n" +
+		"    └── file.txt\n" +
+		"\n2 directories, 1 file\n"
+	if out != expected || status != exitMinor {
+		t.Errorf("Expected %q with status %d but got %q with status %d", expected, exitMinor, out, status)
+	}
+}
@@ -0,0 +1,92 @@
+// Package mkdir implements the functionality for the "mkdir" Unix tool.
+package mkdir
+
+import (
+	"flag"    // Used to parse command-line flags.
+	"io"      // Provides the writer abstraction used for diagnostics.
+	"io/fs"   // For file modes.
+	"os"      // For creating directories and setting their modes.
+	"strconv" // For parsing octal modes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"      // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo" // For converting octal modes.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	parents bool        // -p: create missing parents and accept existing directories.
+	mode    fs.FileMode // -m: mode to give the new directories.
+	setMode bool        // Whether -m was given.
+}
+
+// Run is the entry point for the mkdir functionality.
+// It creates each named directory and returns the exit status: 0 on
+// success, 1 if any directory could not be created, or 2 on invalid
+// options.
+func Run(args []string) int {
+	return run(args, os.Stderr)
+}
+
+// run performs the actual work of Run, reporting errors to stderr.
+func run(args []string, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "mkdir".
+	fset := flag.NewFlagSet("mkdir", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-p" flag to create parent directories as needed.
+	fset.BoolVar(&opts.parents, "p", false, "no error if existing, make parent directories as needed")
+	// Define the "-m" flag to set the mode of the new directories.
+	mode := fset.String("m", "", "set file mode to octal `MODE`, not a=rwx - umask")
+	fset.Parse(args)
+
+	if fset.NArg() == 0 {
+		cli.Fprintf(stderr, "mkdir", "missing operand")
+		return cli.ExitUsage
+	}
+	if *mode != "" {
+		var err error
+		if opts.mode, err = parseMode(*mode); err != nil {
+			cli.Fprintf(stderr, "mkdir", "invalid mode '%s'", *mode)
+			return cli.ExitUsage
+		}
+		opts.setMode = true
+	}
+
+	status := cli.ExitSuccess
+	for _, dir := range fset.Args() {
+		if err := makeDir(dir, &opts); err != nil {
+			cli.Fprintf(stderr, "mkdir", "cannot create directory '%s': %v", dir, cli.Unwrap(err))
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// makeDir creates dir as the options require. An explicit mode is set
+// after creation, so that it is not reduced by the umask; with -p it
+// applies to dir itself but not to the parents created on the way.
+func makeDir(dir string, opts *options) error {
+	var err error
+	if opts.parents {
+		err = os.MkdirAll(dir, 0o777)
+	} else {
+		err = os.Mkdir(dir, 0o777)
+	}
+	if err != nil || !opts.setMode {
+		return err
+	}
+	return os.Chmod(dir, opts.mode)
+}
+
+// parseMode parses an octal mode of up to four digits, such as 755 or
+// 1777, into a FileMode, carrying the setuid, setgid and sticky bits over.
+func parseMode(s string) (fs.FileMode, error) {
+	bits, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	if bits > 0o7777 {
+		return 0, strconv.ErrRange
+	}
+	return fileinfo.FromOctal(uint32(bits)), nil
+}
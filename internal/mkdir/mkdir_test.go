@@ -0,0 +1,71 @@
+package mkdir
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParents(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b", "c")
+	var stderr bytes.Buffer
+	if status := run([]string{"-p", nested}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if info, err := os.Stat(nested); err != nil || !info.IsDir() {
+		t.Fatalf("Expected %s to be a directory: %v", nested, err)
+	}
+	// Existing directories are accepted with -p.
+	if status := run([]string{"-p", nested}, &stderr); status != 0 {
+		t.Errorf("Expected status 0 for an existing directory but got %d: %s", status, stderr.String())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	root := t.TempDir()
+	missingParent := filepath.Join(root, "x", "y")
+	tests := []struct {
+		dir    string
+		stderr string
+	}{
+		{missingParent, "mkdir: cannot create directory '" + missingParent + "': no such file or directory\n"},
+		{root, "mkdir: cannot create directory '" + root + "': file exists\n"},
+	}
+	for _, tt := range tests {
+		var stderr bytes.Buffer
+		status := run([]string{tt.dir}, &stderr)
+		if status != 1 {
+			t.Errorf("mkdir %s: Expected status 1 but got %d", tt.dir, status)
+		}
+		if runtime.GOOS != "windows" && stderr.String() != tt.stderr {
+			t.Errorf("Expected %q but got %q", tt.stderr, stderr.String())
+		}
+	}
+}
+
+func TestMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Permission bits do not apply on Windows")
+	}
+	root := t.TempDir()
+	dir := filepath.Join(root, "p", "private")
+	var stderr bytes.Buffer
+	if status := run([]string{"-p", "-m", "700", dir}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", dir, err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("Expected mode %o but got %o", 0o700, perm)
+	}
+
+	status := run([]string{"-m", "9z", filepath.Join(root, "bad")}, &stderr)
+	if status != 2 {
+		t.Errorf("Expected status 2 for an invalid mode but got %d", status)
+	}
+}
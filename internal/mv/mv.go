@@ -0,0 +1,117 @@
+// Package mv implements the functionality for the "mv" Unix tool.
+package mv
+
+import (
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For formatting error messages.
+	"io"            // Provides the writer abstraction used for diagnostics.
+	"os"            // For renaming and removing files.
+	"path/filepath" // For building target paths.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/cp"  // For copying files between file systems.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	noClobber bool // -n: do not overwrite existing files; wins over -f.
+	force     bool // -f: overwrite existing files, which is the default.
+}
+
+// Run is the entry point for the mv functionality.
+// It moves SOURCE to DEST, or each SOURCE into the directory DEST, and
+// returns the exit status: 0 on success, 1 if anything could not be
+// moved, or 2 on invalid usage.
+func Run(args []string) int {
+	return run(args, os.Stderr)
+}
+
+// run performs the actual work of Run, reporting errors to stderr.
+func run(args []string, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "mv".
+	fset := flag.NewFlagSet("mv", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-n" flag to leave existing files alone.
+	fset.BoolVar(&opts.noClobber, "n", false, "do not overwrite an existing file")
+	// Define the "-f" flag to overwrite existing files without asking.
+	fset.BoolVar(&opts.force, "f", false, "do not prompt before overwriting")
+	fset.Parse(args)
+
+	switch fset.NArg() {
+	case 0:
+		cli.Fprintf(stderr, "mv", "missing file operand")
+		return cli.ExitUsage
+	case 1:
+		cli.Fprintf(stderr, "mv", "missing destination file operand after '%s'", fset.Arg(0))
+		return cli.ExitUsage
+	}
+	sources := fset.Args()[:fset.NArg()-1]
+	dest := fset.Arg(fset.NArg() - 1)
+
+	// Moving into an existing directory keeps the sources' base names;
+	// several sources can only be moved that way.
+	destInfo, err := os.Stat(dest)
+	intoDir := err == nil && destInfo.IsDir()
+	if len(sources) > 1 && !intoDir {
+		cli.Fprintf(stderr, "mv", "target '%s' is not a directory", dest)
+		return cli.ExitFailure
+	}
+
+	status := cli.ExitSuccess
+	for _, src := range sources {
+		target := dest
+		if intoDir {
+			target = filepath.Join(dest, filepath.Base(src))
+		}
+		if err := move(src, target, &opts); err != nil {
+			cli.Fprintf(stderr, "mv", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// move renames src to dst, or, when they are on different file systems,
+// copies src to dst and then removes src.
+func move(src, dst string, opts *options) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return fmt.Errorf("cannot stat '%s': %v", src, cli.Unwrap(err))
+	}
+	if dstInfo, err := os.Lstat(dst); err == nil {
+		if os.SameFile(info, dstInfo) {
+			return fmt.Errorf("'%s' and '%s' are the same file", src, dst)
+		}
+		if opts.noClobber {
+			return nil // With -n, existing files are silently kept.
+		}
+		if dstInfo.IsDir() && !info.IsDir() {
+			return fmt.Errorf("cannot overwrite directory '%s' with non-directory", dst)
+		}
+	}
+	if info.IsDir() && cp.Inside(dst, src) {
+		return fmt.Errorf("cannot move '%s' to a subdirectory of itself, '%s'", src, dst)
+	}
+
+	err = os.Rename(src, dst)
+	if err != nil && isCrossDevice(err) {
+		err = moveByCopy(src, dst)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot move '%s' to '%s': %v", src, dst, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// moveByCopy moves src to dst by copying it and then removing it. Any
+// file in the way is replaced; src is kept if the copy fails.
+func moveByCopy(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if err := cp.CopyAll(src, dst); err != nil {
+		return err
+	}
+	return os.RemoveAll(src)
+}
@@ -0,0 +1,104 @@
+package mv
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+// checkFile fails the test unless path holds content.
+func checkFile(t *testing.T, path, content string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("Failed to read %s: %v", path, err)
+		return
+	}
+	if string(data) != content {
+		t.Errorf("Expected %q in %s but got %q", content, path, data)
+	}
+}
+
+// checkGone fails the test if path still exists.
+func checkGone(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Lstat(path); err == nil {
+		t.Errorf("Expected %s to be gone", path)
+	}
+}
+
+func TestRename(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "old.txt"), filepath.Join(dir, "new.txt")
+	testutil.WriteFile(t, src, "data", 0o644)
+	var stderr bytes.Buffer
+	if status := run([]string{src, dst}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, dst, "data")
+	checkGone(t, src)
+}
+
+func TestMoveIntoDirectory(t *testing.T) {
+	dir := t.TempDir()
+	a, sub := filepath.Join(dir, "a.txt"), filepath.Join(dir, "sub")
+	target := filepath.Join(dir, "target")
+	testutil.WriteFile(t, a, "A", 0o644)
+	testutil.WriteFile(t, filepath.Join(sub, "b.txt"), "B", 0o644)
+	os.Mkdir(target, 0o755)
+
+	var stderr bytes.Buffer
+	if status := run([]string{a, sub, target}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, filepath.Join(target, "a.txt"), "A")
+	checkFile(t, filepath.Join(target, "sub", "b.txt"), "B")
+	checkGone(t, a)
+	checkGone(t, sub)
+
+	// A directory cannot be moved below itself.
+	stderr.Reset()
+	moved := filepath.Join(target, "sub")
+	status := run([]string{moved, filepath.Join(moved, "inner")}, &stderr)
+	expected := "mv: cannot move '" + moved + "' to a subdirectory of itself, '" + filepath.Join(moved, "inner") + "'\n"
+	if status != 1 || stderr.String() != expected {
+		t.Errorf("Expected %q (1) but got %q (%d)", expected, stderr.String(), status)
+	}
+}
+
+func TestNoClobber(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "src.txt"), filepath.Join(dir, "dst.txt")
+	testutil.WriteFile(t, src, "new", 0o644)
+	testutil.WriteFile(t, dst, "old", 0o644)
+
+	var stderr bytes.Buffer
+	if status := run([]string{"-n", src, dst}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, src, "new")
+	checkFile(t, dst, "old")
+
+	// Without -n the existing file is replaced.
+	if status := run([]string{"-f", src, dst}, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	checkFile(t, dst, "new")
+	checkGone(t, src)
+}
+
+func TestMoveByCopy(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	testutil.WriteFile(t, filepath.Join(src, "nested", "f.txt"), "copied", 0o644)
+	testutil.WriteFile(t, dst, "in the way", 0o644)
+
+	if err := moveByCopy(src, dst); err != nil {
+		t.Fatalf("moveByCopy returned error: %v", err)
+	}
+	checkFile(t, filepath.Join(dst, "nested", "f.txt"), "copied")
+	checkGone(t, src)
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package mv
+
+// isCrossDevice reports that no rename failure is known to be caused by
+// crossing file systems on this platform.
+func isCrossDevice(err error) bool {
+	return false
+}
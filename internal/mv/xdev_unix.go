@@ -0,0 +1,14 @@
+//go:build unix
+
+package mv
+
+import (
+	"errors"  // For matching wrapped errors.
+	"syscall" // For the EXDEV error number.
+)
+
+// isCrossDevice reports whether err says that a rename failed because
+// source and target are on different file systems.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
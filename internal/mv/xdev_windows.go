@@ -0,0 +1,16 @@
+package mv
+
+import (
+	"errors"  // For matching wrapped errors.
+	"syscall" // For Windows error numbers.
+)
+
+// errNotSameDevice is ERROR_NOT_SAME_DEVICE, returned when a file is
+// renamed to another volume.
+const errNotSameDevice = syscall.Errno(17)
+
+// isCrossDevice reports whether err says that a rename failed because
+// source and target are on different volumes.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, errNotSameDevice)
+}
@@ -0,0 +1,127 @@
+// Package nl implements the functionality for the "nl" Unix tool.
+package nl
+
+import (
+	"bufio"   // Provides buffered reading and writing.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting line numbers.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"strings" // For padding unnumbered lines and trimming line endings.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Body numbering styles accepted by -b.
+const (
+	styleAll      = "a" // Number every line.
+	styleNonEmpty = "t" // Number only lines that are not empty.
+	styleNone     = "n" // Number no lines.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	style     string // -b: which lines are numbered.
+	width     int    // -w: width of the line number field.
+	separator string // -s: text printed between a line number and its line.
+}
+
+// Run is the entry point for the nl functionality.
+// It copies each file (or stdin) to stdout with its lines numbered,
+// counting on from one file to the next, and returns the exit status: 0
+// on success, 1 if any input could not be read, or 2 on invalid options.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "nl".
+	fset := flag.NewFlagSet("nl", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-b" flag to choose which lines are numbered.
+	fset.StringVar(&opts.style, "b", styleNonEmpty, "use `STYLE` for numbering body lines: a (all), t (non-empty) or n (none)")
+	// Define the "-w" flag to set the width of line numbers.
+	fset.IntVar(&opts.width, "w", 6, "use `WIDTH` columns for line numbers")
+	// Define the "-s" flag to set the text after each line number.
+	fset.StringVar(&opts.separator, "s", "\t", "add `STRING` after (possible) line number")
+	fset.Parse(args)
+
+	switch opts.style {
+	case styleAll, styleNonEmpty, styleNone:
+	default:
+		cli.Fprintf(stderr, "nl", "invalid body numbering style: '%s'", opts.style)
+		return cli.ExitUsage
+	}
+	if opts.width < 1 {
+		cli.Fprintf(stderr, "nl", "invalid line number field width: '%d'", opts.width)
+		return cli.ExitUsage
+	}
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	lineNo := 1
+	for _, file := range files {
+		if err := numberFile(out, file, stdin, &lineNo, &opts); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "nl", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "nl", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// numberFile numbers the lines of the named file, or of stdin for "-".
+func numberFile(w *bufio.Writer, file string, stdin io.Reader, lineNo *int, opts *options) error {
+	if file == "-" {
+		return number(w, stdin, lineNo, opts)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	defer f.Close()
+	if err := number(w, f, lineNo, opts); err != nil {
+		return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// number copies r to w, prefixing the lines selected by the numbering
+// style with the next line number, right-aligned in the field width, and
+// the separator. Other lines are indented by as much, so that the text of
+// all lines stays aligned; lineNo is advanced past the numbers used.
+func number(w *bufio.Writer, r io.Reader, lineNo *int, opts *options) error {
+	blank := strings.Repeat(" ", opts.width+len(opts.separator))
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			text := strings.TrimSuffix(line, "\n")
+			numbered := opts.style == styleAll || (opts.style == styleNonEmpty && text != "")
+			if numbered {
+				fmt.Fprintf(w, "%*d%s%s\n", opts.width, *lineNo, opts.separator, text)
+				*lineNo++
+			} else {
+				w.WriteString(blank + text + "\n")
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
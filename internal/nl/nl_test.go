@@ -0,0 +1,58 @@
+package nl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+func TestStyles(t *testing.T) {
+	input := "one\n\ntwo"
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "     1\tone\n       \n     2\ttwo\n"},
+		{[]string{"-b", "t"}, "     1\tone\n       \n     2\ttwo\n"},
+		{[]string{"-b", "a"}, "     1\tone\n     2\t\n     3\ttwo\n"},
+		{[]string{"-b", "n"}, "       one\n       \n       two\n"},
+		{[]string{"-w", "2", "-b", "a"}, " 1\tone\n 2\t\n 3\ttwo\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, strings.NewReader(input), &stdout, &stderr)
+		if stdout.String() != tt.expected || status != 0 {
+			t.Errorf("nl %v: Expected %q (0) but got %q (%d)", tt.args, tt.expected, stdout.String(), status)
+		}
+	}
+}
+
+func TestSeparator(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	run([]string{"-w", "3", "-s", ": "}, strings.NewReader("a\n\nb\n"), &stdout, &stderr)
+	expected := "  1: a\n     \n  2: b\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestFilesContinueNumbering(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "x\ny\n")
+	b := testutil.TempFile(t, "b.txt", "z\n")
+	var stdout, stderr bytes.Buffer
+	run([]string{"-w", "1", a, b}, nil, &stdout, &stderr)
+	if expected := "1\tx\n2\ty\n3\tz\n"; stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestInvalidStyle(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-b", "x"}, strings.NewReader(""), &stdout, &stderr)
+	expected := "nl: invalid body numbering style: 'x'\n"
+	if stderr.String() != expected || status != 2 {
+		t.Errorf("Expected %q (2) but got %q (%d)", expected, stderr.String(), status)
+	}
+}
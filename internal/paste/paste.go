@@ -0,0 +1,175 @@
+// Package paste implements the functionality for the "paste" Unix tool.
+package paste
+
+import (
+	"bufio"   // Provides buffered reading and writing.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting error messages.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"strings" // For trimming line endings.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the paste functionality.
+// It prints the corresponding lines of each file (or stdin) side by side,
+// separated by tabs, or with -s each file's lines on a single line. It
+// returns the exit status: 0 on success, or 1 if any file could not be
+// read.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "paste".
+	fset := flag.NewFlagSet("paste", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-d" flag to choose the delimiters.
+	list := fset.String("d", "\t", "reuse characters from `LIST` instead of tabs")
+	// Define the "-s" flag to paste one file at a time.
+	serial := fset.Bool("s", false, "paste one file at a time instead of in parallel")
+	fset.Parse(args)
+
+	delims := parseDelimiters(*list)
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	// Every "-" reads from the same stdin, each taking the next line in turn.
+	stdinReader := bufio.NewReader(stdin)
+	readers := make([]*bufio.Reader, len(files))
+	for i, file := range files {
+		if file == "-" {
+			readers[i] = stdinReader
+			continue
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			cli.Fprintf(stderr, "paste", "%s: %v", file, cli.Unwrap(err))
+			return cli.ExitFailure
+		}
+		defer f.Close()
+		readers[i] = bufio.NewReader(f)
+	}
+
+	out := bufio.NewWriter(stdout)
+	var err error
+	if *serial {
+		err = pasteSerial(out, files, readers, delims)
+	} else {
+		err = pasteParallel(out, files, readers, delims)
+	}
+	if err != nil {
+		out.Flush()
+		cli.Fprintf(stderr, "paste", "%v", err)
+		return cli.ExitFailure
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "paste", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// parseDelimiters splits the argument of -d into the delimiters it lists,
+// one per character. The escapes \n, \t and \\ stand for a newline, a tab
+// and a backslash, and \0 for no delimiter at all.
+func parseDelimiters(list string) []string {
+	var delims []string
+	runes := []rune(list)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' || i == len(runes)-1 {
+			delims = append(delims, string(runes[i]))
+			continue
+		}
+		i++
+		switch runes[i] {
+		case 'n':
+			delims = append(delims, "\n")
+		case 't':
+			delims = append(delims, "\t")
+		case '0':
+			delims = append(delims, "")
+		default:
+			delims = append(delims, string(runes[i]))
+		}
+	}
+	if len(delims) == 0 {
+		delims = []string{""} // An empty list joins lines with nothing.
+	}
+	return delims
+}
+
+// readLine returns the next line of r without its line ending; ok is
+// false once r has no more lines.
+func readLine(r *bufio.Reader) (line string, ok bool, err error) {
+	line, err = r.ReadString('\n')
+	if err == io.EOF {
+		err = nil
+		if line == "" {
+			return "", false, nil
+		}
+	}
+	return strings.TrimSuffix(line, "\n"), true, err
+}
+
+// pasteParallel prints line n of every file on output line n, joined by
+// the delimiters in turn. Files that have run out contribute empty
+// fields, until all of them have.
+func pasteParallel(w *bufio.Writer, files []string, readers []*bufio.Reader, delims []string) error {
+	done := make([]bool, len(readers))
+	for {
+		var fields []string
+		remaining := false
+		for i, r := range readers {
+			var line string
+			if !done[i] {
+				var ok bool
+				var err error
+				if line, ok, err = readLine(r); err != nil {
+					return fmt.Errorf("%s: %v", files[i], cli.Unwrap(err))
+				}
+				done[i] = !ok
+				remaining = remaining || ok
+			}
+			fields = append(fields, line)
+		}
+		if !remaining {
+			return nil
+		}
+		for i, field := range fields {
+			if i > 0 {
+				w.WriteString(delims[(i-1)%len(delims)])
+			}
+			w.WriteString(field)
+		}
+		w.WriteString("\n")
+	}
+}
+
+// pasteSerial prints all the lines of each file on one output line,
+// joined by the delimiters in turn.
+func pasteSerial(w *bufio.Writer, files []string, readers []*bufio.Reader, delims []string) error {
+	for i, r := range readers {
+		for n := 0; ; n++ {
+			line, ok, err := readLine(r)
+			if err != nil {
+				return fmt.Errorf("%s: %v", files[i], cli.Unwrap(err))
+			}
+			if !ok {
+				break
+			}
+			if n > 0 {
+				w.WriteString(delims[(n-1)%len(delims)])
+			}
+			w.WriteString(line)
+		}
+		w.WriteString("\n")
+	}
+	return nil
+}
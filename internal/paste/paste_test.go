@@ -0,0 +1,37 @@
+package paste
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+func TestPaste(t *testing.T) {
+	nums := testutil.TempFile(t, "nums", "1\n2\n3\n")
+	letters := testutil.TempFile(t, "letters", "a\nb")
+	words := testutil.TempFile(t, "words", "one\n")
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{[]string{nums, letters}, "", "1\ta\n2\tb\n3\t\n"},
+		{[]string{letters, words, nums}, "", "a\tone\t1\nb\t\t2\n\t\t3\n"},
+		{[]string{"-d", ",:", nums, letters, words}, "", "1,a:one\n2,b:\n3,:\n"},
+		{[]string{"-d", `\0`, nums, letters}, "", "1a\n2b\n3\n"},
+		{[]string{"-s", nums, letters}, "", "1\t2\t3\na\tb\n"},
+		{[]string{"-s", "-d", `,\n`, nums}, "", "1,2\n3\n"},
+		{[]string{"-", "-"}, "x\ny\nz\n", "x\ty\nz\t\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); status != 0 {
+			t.Fatalf("paste %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("paste %v: Expected %q but got %q", tt.args, tt.expected, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package pwd implements the functionality for the "pwd" Unix tool.
+package pwd
+
+import (
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For printing the directory.
+	"io"            // Provides the writer abstraction used for output.
+	"os"            // For the working directory and the environment.
+	"path/filepath" // For resolving symbolic links.
+	"slices"        // For inspecting path components.
+	"strings"       // For splitting paths into components.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the pwd functionality.
+// It prints the name of the current working directory: $PWD when that is
+// a valid name for it (-L), and otherwise, or with -P, the name with all
+// symbolic links resolved. It returns the exit status: 0 on success, or 1 if the directory could not be found.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "pwd".
+	fs := flag.NewFlagSet("pwd", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	// Define the "-L" flag to print the logical directory, the default.
+	fs.Bool("L", true, "use PWD from the environment, even if it contains symlinks")
+	// Define the "-P" flag to print the physical directory; it wins over -L.
+	physical := fs.Bool("P", false, "avoid all symlinks")
+	fs.Parse(args)
+
+	dir, err := os.Getwd()
+	if err != nil {
+		cli.Fprintf(stderr, "pwd", "cannot determine current directory: %v", err)
+		return cli.ExitFailure
+	}
+	if !*physical {
+		if logical, ok := logicalDir(dir); ok {
+			fmt.Fprintln(stdout, logical)
+			return cli.ExitSuccess
+		}
+	}
+	// Getwd itself may answer with $PWD, so resolve whatever it returned.
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		cli.Fprintf(stderr, "pwd", "cannot resolve current directory: %v", err)
+		return cli.ExitFailure
+	}
+	fmt.Fprintln(stdout, dir)
+	return cli.ExitSuccess
+}
+
+// logicalDir returns $PWD if it names the working directory dir, possibly
+// through symbolic links. Like POSIX pwd -L, it ignores $PWD unless it
+// is absolute and free of "." and ".." components.
+func logicalDir(dir string) (string, bool) {
+	pwd := os.Getenv("PWD")
+	if !filepath.IsAbs(pwd) {
+		return "", false
+	}
+	parts := strings.Split(filepath.ToSlash(pwd), "/")
+	if slices.Contains(parts, ".") || slices.Contains(parts, "..") {
+		return "", false
+	}
+	pwdInfo, err1 := os.Stat(pwd)
+	dirInfo, err2 := os.Stat(dir)
+	if err1 != nil || err2 != nil || !os.SameFile(pwdInfo, dirInfo) {
+		return "", false
+	}
+	return pwd, true
+}
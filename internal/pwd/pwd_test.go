@@ -0,0 +1,62 @@
+package pwd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogicalAndPhysical(t *testing.T) {
+	root := t.TempDir()
+	real := filepath.Join(root, "real")
+	link := filepath.Join(root, "link")
+	if err := os.Mkdir(real, 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	physical, err := filepath.EvalSymlinks(real)
+	if err != nil {
+		t.Fatalf("Failed to resolve %s: %v", real, err)
+	}
+	t.Chdir(link)
+	t.Setenv("PWD", link)
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, link},
+		{[]string{"-L"}, link},
+		{[]string{"-P"}, physical},
+		{[]string{"-L", "-P"}, physical},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, &stdout, &stderr); status != 0 {
+			t.Errorf("pwd %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if got := stdout.String(); got != tt.expected+"\n" {
+			t.Errorf("pwd %v: Expected %q but got %q", tt.args, tt.expected+"\n", got)
+		}
+	}
+}
+
+func TestStalePWD(t *testing.T) {
+	dir := t.TempDir()
+	physical, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatalf("Failed to resolve %s: %v", dir, err)
+	}
+	t.Chdir(dir)
+	for _, pwd := range []string{os.TempDir(), "relative", dir + string(filepath.Separator) + ".." + string(filepath.Separator) + filepath.Base(dir)} {
+		t.Setenv("PWD", pwd)
+		var stdout, stderr bytes.Buffer
+		run(nil, &stdout, &stderr)
+		if got := stdout.String(); got != physical+"\n" {
+			t.Errorf("With $PWD=%q: Expected %q but got %q", pwd, physical+"\n", got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package rev implements the functionality for the "rev" Unix tool.
+package rev
+
+import (
+	"bufio"        // Provides buffered reading and writing.
+	"flag"         // Used to parse command-line flags.
+	"fmt"          // For formatting error messages.
+	"io"           // Provides the reader and writer abstractions used for input and output.
+	"os"           // For interacting with the file system and OS I/O.
+	"unicode/utf8" // For reversing lines character by character.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the rev functionality.
+// It prints the lines of each file (or stdin) with their characters in
+// reverse order and returns the exit status: 0 on success, or 1 if any
+// input could not be read.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "rev".
+	fset := flag.NewFlagSet("rev", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	fset.Parse(args)
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for _, file := range files {
+		if err := revFile(out, file, stdin); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "rev", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "rev", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// revFile reverses the lines of the named file, or of stdin for "-".
+func revFile(w *bufio.Writer, file string, stdin io.Reader) error {
+	if file == "-" {
+		return rev(w, stdin)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %v", file, cli.Unwrap(err))
+	}
+	defer f.Close()
+	if err := rev(w, f); err != nil {
+		return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// rev copies r to w with every line reversed. The newline ending a line
+// stays at its end, and a final line without one is printed without one.
+func rev(w *bufio.Writer, r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			newline := line[len(line)-1] == '\n'
+			if newline {
+				line = line[:len(line)-1]
+			}
+			w.Write(reverse(line))
+			if newline {
+				w.WriteByte('\n')
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// reverse returns the characters of line in reverse order. Multibyte
+// UTF-8 sequences are kept intact, and bytes that are not valid UTF-8 are
+// moved one at a time, so nothing is lost or replaced.
+func reverse(line []byte) []byte {
+	reversed := make([]byte, len(line))
+	end := len(reversed)
+	for len(line) > 0 {
+		_, size := utf8.DecodeRune(line)
+		end -= size
+		copy(reversed[end:], line[:size])
+		line = line[size:]
+	}
+	return reversed
+}
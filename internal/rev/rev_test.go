@@ -0,0 +1,45 @@
+package rev
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello world\n", "dlrow olleh\n"},
+		{"héllo, 世界\n", "界世 ,olléh\n"},
+		{"ab\ncd", "ba\ndc"},
+		{"\n\nx\n", "\n\nx\n"},
+		{"a\xffb\n", "b\xffa\n"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(nil, strings.NewReader(tt.input), &stdout, &stderr)
+		if stdout.String() != tt.expected || status != 0 {
+			t.Errorf("rev %q: Expected %q (0) but got %q (%d)", tt.input, tt.expected, stdout.String(), status)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	os.WriteFile(a, []byte("abc\n"), 0o644)
+	missing := filepath.Join(dir, "missing")
+	var stdout, stderr bytes.Buffer
+	status := run([]string{missing, a}, nil, &stdout, &stderr)
+	if stdout.String() != "cba\n" || status != 1 {
+		t.Errorf("Expected %q (1) but got %q (%d)", "cba\n", stdout.String(), status)
+	}
+	if expected := "rev: cannot open " + missing + ": no such file or directory\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
@@ -0,0 +1,137 @@
+// Package rm implements the functionality for the "rm" Unix tool.
+package rm
+
+import (
+	"bufio"         // For reading answers to prompts.
+	"errors"        // For recognizing missing-file errors.
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For printing prompts.
+	"io"            // Provides the reader and writer abstractions used for prompts and diagnostics.
+	"io/fs"         // For the missing-file error.
+	"os"            // For removing files and directories.
+	"path/filepath" // For recognizing the root and dot directories.
+	"strings"       // For interpreting answers to prompts.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	recursive   bool // -r, -R: remove directories and their contents.
+	force       bool // -f: ignore missing files and never prompt.
+	interactive bool // -i: prompt before every removal.
+}
+
+// Run is the entry point for the rm functionality.
+// It removes each named file (and with -r, directory) and returns the
+// exit status: 0 on success, or 1 if anything could not be removed.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stderr)
+}
+
+// run performs the actual work of Run, reading answers to prompts from
+// stdin and writing prompts and diagnostics to stderr.
+func run(args []string, stdin io.Reader, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "rm".
+	fset := flag.NewFlagSet("rm", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-r" and "-R" flags to remove directories recursively.
+	fset.BoolVar(&opts.recursive, "r", false, "remove directories and their contents recursively")
+	fset.BoolVar(&opts.recursive, "R", false, "same as -r")
+	// Define the "-f" flag to ignore missing files and never prompt.
+	fset.BoolVar(&opts.force, "f", false, "ignore nonexistent files, never prompt")
+	// Define the "-i" flag to prompt before every removal.
+	fset.BoolVar(&opts.interactive, "i", false, "prompt before every removal")
+	fset.Parse(args)
+	// -f overrides -i, so that rm never waits on input.
+	if opts.force {
+		opts.interactive = false
+	}
+
+	if fset.NArg() == 0 {
+		if opts.force {
+			return cli.ExitSuccess
+		}
+		cli.Fprintf(stderr, "rm", "missing operand")
+		return cli.ExitUsage
+	}
+
+	answers := bufio.NewReader(stdin)
+	status := cli.ExitSuccess
+	for _, name := range fset.Args() {
+		if err := remove(name, answers, stderr, &opts); err != nil {
+			cli.Fprintf(stderr, "rm", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// remove removes name as the options allow, first asking for
+// confirmation with -i. A directory is only removed with -r, and then
+// together with everything in it; the confirmation covers the whole tree.
+func remove(name string, answers *bufio.Reader, prompt io.Writer, opts *options) error {
+	if isDot(name) {
+		return fmt.Errorf("refusing to remove '.' or '..' directory: skipping '%s'", name)
+	}
+	if isRoot(name) {
+		return fmt.Errorf("it is dangerous to operate recursively on '%s'", name)
+	}
+	info, err := os.Lstat(name)
+	if err != nil {
+		if opts.force && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("cannot remove '%s': %v", name, cli.Unwrap(err))
+	}
+	if info.IsDir() && !opts.recursive {
+		return fmt.Errorf("cannot remove '%s': Is a directory", name)
+	}
+
+	if opts.interactive {
+		kind := "file"
+		if info.IsDir() {
+			kind = "directory"
+		}
+		fmt.Fprintf(prompt, "rm: remove %s '%s'? ", kind, name)
+		if !confirmed(answers) {
+			return nil
+		}
+	}
+
+	if info.IsDir() {
+		err = os.RemoveAll(name)
+	} else {
+		err = os.Remove(name)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot remove '%s': %v", name, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// confirmed reads a line from answers and reports whether it starts
+// with 'y' or 'Y'.
+func confirmed(answers *bufio.Reader) bool {
+	line, _ := answers.ReadString('\n')
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(line)), "y")
+}
+
+// isDot reports whether the last element of name is "." or "..", which
+// rm refuses to remove since that would remove the current directory or
+// its parent.
+func isDot(name string) bool {
+	base := filepath.Base(name)
+	return base == "." || base == ".."
+}
+
+// isRoot reports whether name refers to the root directory of its
+// volume, which rm refuses to remove.
+func isRoot(name string) bool {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return false
+	}
+	return abs == filepath.VolumeName(abs)+string(filepath.Separator)
+}
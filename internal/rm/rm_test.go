@@ -0,0 +1,111 @@
+package rm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeTree creates a directory holding a file and a nested directory,
+// and returns its path.
+func makeTree(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "tree")
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"a.txt", filepath.Join("sub", "b.txt")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("Failed to write fixture: %v", err)
+		}
+	}
+	return dir
+}
+
+// exists reports whether path exists.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func TestRecursive(t *testing.T) {
+	dir := makeTree(t)
+	var stderr bytes.Buffer
+	status := run([]string{dir}, nil, &stderr)
+	expected := "rm: cannot remove '" + dir + "': Is a directory\n"
+	if status != 1 || stderr.String() != expected {
+		t.Errorf("Expected %q (1) but got %q (%d)", expected, stderr.String(), status)
+	}
+	if !exists(dir) {
+		t.Fatalf("Expected %s to be kept without -r", dir)
+	}
+
+	stderr.Reset()
+	if status := run([]string{"-r", dir}, nil, &stderr); status != 0 {
+		t.Errorf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if exists(dir) {
+		t.Errorf("Expected %s to be removed", dir)
+	}
+}
+
+func TestForceMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	var stderr bytes.Buffer
+	status := run([]string{missing}, nil, &stderr)
+	expected := "rm: cannot remove '" + missing + "': no such file or directory\n"
+	if status != 1 || stderr.String() != expected {
+		t.Errorf("Expected %q (1) but got %q (%d)", expected, stderr.String(), status)
+	}
+
+	stderr.Reset()
+	if status := run([]string{"-f", missing}, nil, &stderr); status != 0 || stderr.Len() != 0 {
+		t.Errorf("Expected status 0 and no output but got %d: %q", status, stderr.String())
+	}
+}
+
+func TestInteractive(t *testing.T) {
+	dir := makeTree(t)
+	keep, drop := filepath.Join(dir, "a.txt"), filepath.Join(dir, "sub")
+	var stderr bytes.Buffer
+	status := run([]string{"-i", "-r", keep, drop}, strings.NewReader("n\ny\n"), &stderr)
+	if status != 0 {
+		t.Errorf("Expected status 0 but got %d", status)
+	}
+	expected := "rm: remove file '" + keep + "'? rm: remove directory '" + drop + "'? "
+	if stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+	if !exists(keep) || exists(drop) {
+		t.Errorf("Expected only %s to be removed", drop)
+	}
+}
+
+func TestRefuseRoot(t *testing.T) {
+	// Without -r a failing guard could do no harm here.
+	root := string(filepath.Separator)
+	var stderr bytes.Buffer
+	status := run([]string{root}, nil, &stderr)
+	expected := "rm: it is dangerous to operate recursively on '" + root + "'\n"
+	if status != 1 || stderr.String() != expected {
+		t.Errorf("Expected %q (1) but got %q (%d)", expected, stderr.String(), status)
+	}
+}
+
+func TestRefuseDot(t *testing.T) {
+	dir := makeTree(t)
+	t.Chdir(filepath.Join(dir, "sub"))
+	for _, name := range []string{".", "..", "../", "../sub/.."} {
+		var stderr bytes.Buffer
+		status := run([]string{"-r", name}, nil, &stderr)
+		expected := "rm: refusing to remove '.' or '..' directory: skipping '" + name + "'\n"
+		if status != 1 || stderr.String() != expected {
+			t.Errorf("rm -r %s: Expected %q (1) but got %q (%d)", name, expected, stderr.String(), status)
+		}
+	}
+	if !exists(filepath.Join(dir, "a.txt")) || !exists(filepath.Join(dir, "sub", "b.txt")) {
+		t.Errorf("Expected the contents of %s to be kept", dir)
+	}
+}
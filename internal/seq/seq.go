@@ -0,0 +1,165 @@
+// Package seq implements the functionality for the "seq" Unix tool.
+package seq
+
+import (
+	"bufio"   // Provides buffered writing.
+	"flag"    // Used to parse command-line flags.
+	"io"      // Provides the writer abstraction used for output.
+	"math"    // For scaling and rounding the numbers.
+	"os"      // For the standard streams.
+	"strconv" // For parsing and formatting numbers.
+	"strings" // For measuring decimal places and padding.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// maxDecimals caps the number of decimal places the numbers are
+// computed with.
+const maxDecimals = 15
+
+// operand is a number given on the command line.
+type operand struct {
+	value    float64 // The number itself.
+	decimals int     // How many digits it has after the decimal point.
+}
+
+// Run is the entry point for the seq functionality.
+// It prints the numbers from FIRST (default 1) to LAST in steps of STEP
+// (default 1) and returns the exit status: 0 on success, 1 on a write
+// error, or 2 on invalid operands.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "seq".
+	fs := flag.NewFlagSet("seq", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	// Define the "-s" flag to set the text printed between numbers.
+	sep := fs.String("s", "\n", "use `STRING` to separate numbers")
+	// Define the "-w" flag to pad the numbers with leading zeros.
+	equalWidth := fs.Bool("w", false, "equalize width by padding with leading zeros")
+	fs.Parse(separateNegatives(args))
+
+	if fs.NArg() == 0 {
+		cli.Fprintf(stderr, "seq", "missing operand")
+		return cli.ExitUsage
+	}
+	if fs.NArg() > 3 {
+		cli.Fprintf(stderr, "seq", "extra operand '%s'", fs.Arg(3))
+		return cli.ExitUsage
+	}
+	var ops []operand
+	for _, arg := range fs.Args() {
+		op, err := parseOperand(arg)
+		if err != nil {
+			cli.Fprintf(stderr, "seq", "invalid floating point argument: '%s'", arg)
+			return cli.ExitUsage
+		}
+		ops = append(ops, op)
+	}
+
+	// Fill in the defaults: LAST, FIRST LAST, or FIRST STEP LAST.
+	first, step, last := operand{value: 1}, operand{value: 1}, ops[len(ops)-1]
+	if len(ops) > 1 {
+		first = ops[0]
+	}
+	if len(ops) == 3 {
+		step = ops[1]
+	}
+	if step.value == 0 {
+		cli.Fprintf(stderr, "seq", "invalid Zero increment value: '%s'", fs.Arg(1))
+		return cli.ExitUsage
+	}
+
+	out := bufio.NewWriter(stdout)
+	printSequence(out, first, step, last, *sep, *equalWidth)
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "seq", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// printSequence prints the numbers from first to last in steps of step,
+// separated by sep and followed by a newline, with as many decimal places
+// as first or step has. To avoid the rounding errors of repeated floating
+// point addition, the numbers are computed as whole multiples of the
+// smallest decimal place any operand uses.
+func printSequence(w *bufio.Writer, first, step, last operand, sep string, equalWidth bool) {
+	decimals := max(first.decimals, step.decimals)
+	scale := math.Pow10(min(max(decimals, last.decimals), maxDecimals))
+	start := math.Round(first.value * scale)
+	inc := math.Round(step.value * scale)
+	end := math.Round(last.value * scale)
+
+	// The sequence is empty when last lies behind first.
+	span := (end - start) / inc
+	if span < 0 {
+		return
+	}
+	count := math.Floor(span) + 1
+
+	format := func(i float64) string {
+		return strconv.FormatFloat((start+i*inc)/scale, 'f', decimals, 64)
+	}
+	// With -w, pad to the widest of the first and last numbers printed and
+	// of LAST itself cut to the same precision, as GNU seq does.
+	width := 0
+	if equalWidth {
+		cut := math.Trunc(last.value*math.Pow10(decimals)) / math.Pow10(decimals)
+		lastWidth := len(strconv.FormatFloat(cut, 'f', decimals, 64))
+		width = max(len(format(0)), len(format(count-1)), lastWidth)
+	}
+	for i := float64(0); i < count; i++ {
+		if i > 0 {
+			w.WriteString(sep)
+		}
+		w.WriteString(pad(format(i), width))
+	}
+	w.WriteString("\n")
+}
+
+// parseOperand parses a number and counts its decimal places.
+func parseOperand(arg string) (operand, error) {
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return operand{}, strconv.ErrSyntax
+	}
+	op := operand{value: value}
+	if _, fraction, ok := strings.Cut(arg, "."); ok && !strings.ContainsAny(arg, "eE") {
+		op.decimals = min(len(fraction), maxDecimals)
+	}
+	return op, nil
+}
+
+// pad left-pads the number s with zeros to width, after any minus sign.
+func pad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	zeros := strings.Repeat("0", width-len(s))
+	if rest, negative := strings.CutPrefix(s, "-"); negative {
+		return "-" + zeros + rest
+	}
+	return zeros + s
+}
+
+// separateNegatives returns args with "--" inserted before the first
+// negative number that is not the value of -s, so that the flag parser
+// takes it, and everything after it, as operands rather than flags.
+func separateNegatives(args []string) []string {
+	for i, arg := range args {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return args
+		}
+		if i > 0 && args[i-1] == "-s" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(arg, 64); err == nil {
+			return append(append(args[:i:i], "--"), args[i:]...)
+		}
+	}
+	return args
+}
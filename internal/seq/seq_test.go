@@ -0,0 +1,105 @@
+package seq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// runSeq runs seq with args and returns its output and status.
+func runSeq(args ...string) (string, int) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, &stdout, &stderr)
+	return stdout.String() + stderr.String(), status
+}
+
+func TestIntegers(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"3"}, "1\n2\n3\n"},
+		{[]string{"2", "4"}, "2\n3\n4\n"},
+		{[]string{"1", "3", "10"}, "1\n4\n7\n10\n"},
+		{[]string{"1", "3", "9"}, "1\n4\n7\n"},
+		{[]string{"5", "1"}, ""},
+		{[]string{"0"}, ""},
+		{[]string{"-2", "0"}, "-2\n-1\n0\n"},
+		{[]string{"-s", ",", "3"}, "1,2,3\n"},
+		{[]string{"-s", "-", "3"}, "1-2-3\n"},
+	}
+	for _, tt := range tests {
+		if got, status := runSeq(tt.args...); got != tt.expected || status != 0 {
+			t.Errorf("seq %q: Expected %q (0) but got %q (%d)", tt.args, tt.expected, got, status)
+		}
+	}
+}
+
+func TestFloats(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"0", "0.1", "0.3"}, "0.0\n0.1\n0.2\n0.3\n"},
+		{[]string{"1", "0.5", "2.2"}, "1.0\n1.5\n2.0\n"},
+		{[]string{"0.25", "1"}, "0.25\n"},
+		{[]string{"1", "1.5"}, "1\n"},
+	}
+	for _, tt := range tests {
+		if got, status := runSeq(tt.args...); got != tt.expected || status != 0 {
+			t.Errorf("seq %q: Expected %q (0) but got %q (%d)", tt.args, tt.expected, got, status)
+		}
+	}
+}
+
+func TestNegativeStep(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"5", "-2", "1"}, "5\n3\n1\n"},
+		{[]string{"5", "-2", "0"}, "5\n3\n1\n"},
+		{[]string{"1", "-0.5", "0"}, "1.0\n0.5\n0.0\n"},
+		{[]string{"1", "-1", "2"}, ""},
+	}
+	for _, tt := range tests {
+		if got, status := runSeq(tt.args...); got != tt.expected || status != 0 {
+			t.Errorf("seq %q: Expected %q (0) but got %q (%d)", tt.args, tt.expected, got, status)
+		}
+	}
+}
+
+func TestEqualWidth(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-w", "8", "10"}, "08\n09\n10\n"},
+		{[]string{"-w", "1", "5", "100"}, "001\n006\n"},
+		{[]string{"-w", "1", "5", "99.5"}, "01\n06\n"},
+		{[]string{"-w", "-1", "1"}, "-1\n00\n01\n"},
+		{[]string{"-w", "-s", " ", "0.5", "0.5", "1.5"}, "0.5 1.0 1.5\n"},
+	}
+	for _, tt := range tests {
+		got, status := runSeq(tt.args...)
+		if !strings.HasPrefix(got, tt.expected) || status != 0 {
+			t.Errorf("seq %q: Expected %q (0) but got %q (%d)", tt.args, tt.expected, got, status)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "seq: missing operand\n"},
+		{[]string{"x"}, "seq: invalid floating point argument: 'x'\n"},
+		{[]string{"1", "0", "5"}, "seq: invalid Zero increment value: '0'\n"},
+	}
+	for _, tt := range tests {
+		if got, status := runSeq(tt.args...); got != tt.expected || status != 2 {
+			t.Errorf("seq %q: Expected %q (2) but got %q (%d)", tt.args, tt.expected, got, status)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package sort implements the functionality for the "sort" Unix tool.
+package sort
+
+import (
+	"bufio"   // Provides buffered reading and writing.
+	"cmp"     // For three-way comparison of keys.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting error messages.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"sort"    // For stable sorting of the lines.
+	"strconv" // For parsing numbers and key specifications.
+	"strings" // For splitting fields and folding case.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	reverse  bool // -r: reverse the result of comparisons.
+	numeric  bool // -n: compare by leading numeric value.
+	unique   bool // -u: output only the first of lines that compare equal.
+	foldCase bool // -f: fold lower case to upper case when comparing.
+	keyStart int  // -k: first field of the sort key, counting from 1; 0 for the whole line.
+	keyEnd   int  // -k: last field of the sort key; 0 for the end of the line.
+}
+
+// Run is the entry point for the sort functionality.
+// It writes the lines of all files (or stdin) together in sorted order
+// and returns the exit status: 0 on success, 1 if any input could not be
+// read, or 2 on invalid options.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "sort".
+	fset := flag.NewFlagSet("sort", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-r" flag to reverse the order.
+	fset.BoolVar(&opts.reverse, "r", false, "reverse the result of comparisons")
+	// Define the "-n" flag to compare numbers by value.
+	fset.BoolVar(&opts.numeric, "n", false, "compare according to string numerical value")
+	// Define the "-u" flag to drop lines that compare equal.
+	fset.BoolVar(&opts.unique, "u", false, "output only the first of an equal run")
+	// Define the "-f" flag to ignore case.
+	fset.BoolVar(&opts.foldCase, "f", false, "fold lower case to upper case characters")
+	// Define the "-k" flag to sort by a field rather than the whole line.
+	key := fset.String("k", "", "sort via a key starting at field `N[,M]`, up to field M or the end of the line")
+	fset.Parse(args)
+
+	if *key != "" {
+		var err error
+		if opts.keyStart, opts.keyEnd, err = parseKey(*key); err != nil {
+			cli.Fprintf(stderr, "sort", "invalid key specification: '%s'", *key)
+			return cli.ExitUsage
+		}
+	}
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	// Gather the lines of every input, so they are sorted together.
+	status := cli.ExitSuccess
+	var lines []string
+	for _, file := range files {
+		var err error
+		if lines, err = readFile(lines, file, stdin); err != nil {
+			cli.Fprintf(stderr, "sort", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+
+	sortLines(lines, &opts)
+	out := bufio.NewWriter(stdout)
+	for i, line := range lines {
+		// With -u, keep only the first line of each run of equal keys.
+		if opts.unique && i > 0 && compareKeys(lines[i-1], line, &opts) == 0 {
+			continue
+		}
+		out.WriteString(line + "\n")
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "sort", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// parseKey parses a key specification "N" or "N,M" into its first and
+// last fields; a last field of 0 means the end of the line.
+func parseKey(spec string) (start, end int, err error) {
+	first, last, hasEnd := strings.Cut(spec, ",")
+	if start, err = strconv.Atoi(first); err != nil || start < 1 {
+		return 0, 0, strconv.ErrSyntax
+	}
+	if hasEnd {
+		if end, err = strconv.Atoi(last); err != nil || end < start {
+			return 0, 0, strconv.ErrSyntax
+		}
+	}
+	return start, end, nil
+}
+
+// readFile appends the lines of the named file, or of stdin for "-", to
+// lines, without their line endings.
+func readFile(lines []string, file string, stdin io.Reader) ([]string, error) {
+	if file == "-" {
+		return readLines(lines, stdin)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return lines, fmt.Errorf("cannot read: %s: %v", file, cli.Unwrap(err))
+	}
+	defer f.Close()
+	lines, err = readLines(lines, f)
+	if err != nil {
+		return lines, fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	return lines, nil
+}
+
+// readLines appends the lines of r to lines. A final line without a
+// newline counts as a line.
+func readLines(lines []string, r io.Reader) ([]string, error) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+// sortLines sorts lines by their keys as the options select. Lines whose
+// keys are equal are ordered by their whole text as a last resort, unless
+// -u is given, in which case they keep their input order so that the
+// first of them is the one printed.
+func sortLines(lines []string, opts *options) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		c := compareKeys(lines[i], lines[j], opts)
+		if c == 0 && !opts.unique {
+			c = strings.Compare(lines[i], lines[j])
+		}
+		if opts.reverse {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// compareKeys compares the sort keys of a and b, returning -1, 0 or 1.
+func compareKeys(a, b string, opts *options) int {
+	ka, kb := sortKey(a, opts), sortKey(b, opts)
+	if opts.numeric {
+		return cmp.Compare(leadingNumber(ka), leadingNumber(kb))
+	}
+	if opts.foldCase {
+		ka, kb = strings.ToUpper(ka), strings.ToUpper(kb)
+	}
+	return strings.Compare(ka, kb)
+}
+
+// sortKey returns the part of line that -k selects: from the start of
+// field keyStart, including the blanks before it, to the end of field
+// keyEnd or of the line. Fields are separated by runs of blanks. As in
+// GNU sort, the key starts right after field keyStart-1, so blanks
+// trailing that field form the key even when field keyStart is missing.
+func sortKey(line string, opts *options) string {
+	if opts.keyStart == 0 {
+		return line
+	}
+	start, end := skipFields(line, opts.keyStart-1), len(line)
+	if opts.keyEnd > 0 {
+		end = skipFields(line, opts.keyEnd)
+	}
+	if start > end {
+		return ""
+	}
+	return line[start:end]
+}
+
+// skipFields returns the offset in line just past its first n fields,
+// each made of the blanks before it and its non-blank characters.
+func skipFields(line string, n int) int {
+	i := 0
+	for ; n > 0; n-- {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+	}
+	return i
+}
+
+// leadingNumber returns the value of the number at the start of s, after
+// any blanks; text without one counts as zero.
+func leadingNumber(s string) float64 {
+	s = strings.TrimLeft(s, " \t")
+	end := 0
+	if end < len(s) && (s[end] == '-' || s[end] == '+') {
+		end++
+	}
+	for end < len(s) && (isDigit(s[end]) || s[end] == '.') {
+		end++
+	}
+	// Drop characters the number cannot end with, such as a lone sign.
+	for end > 0 {
+		if n, err := strconv.ParseFloat(s[:end], 64); err == nil {
+			return n
+		}
+		end--
+	}
+	return 0
+}
+
+// isDigit reports whether b is an ASCII digit.
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
@@ -0,0 +1,89 @@
+package sort
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runSort runs sort with args over input and returns its output.
+func runSort(t *testing.T, input string, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if status := run(args, strings.NewReader(input), &stdout, &stderr); status != 0 {
+		t.Fatalf("sort %v exited with %d: %s", args, status, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestModes(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{nil, "pear\napple\nBanana\ncherry", "Banana\napple\ncherry\npear\n"},
+		{[]string{"-f"}, "pear\napple\nBanana\ncherry\n", "apple\nBanana\ncherry\npear\n"},
+		{[]string{"-r"}, "b\nc\na\n", "c\nb\na\n"},
+		{nil, "10\n9\n100\n-1\n", "-1\n10\n100\n9\n"},
+		{[]string{"-n"}, "10\n9\n100\n-1\nx\n2.5\n", "-1\nx\n2.5\n9\n10\n100\n"},
+		{[]string{"-n", "-r"}, "10\n9\n100\n", "100\n10\n9\n"},
+		{[]string{"-u"}, "b\na\nb\na\nc\n", "a\nb\nc\n"},
+		{[]string{"-u", "-f"}, "b\nB\na\n", "a\nb\n"},
+		{[]string{"-n", "-u"}, "1\n01\n2\n", "1\n2\n"},
+	}
+	for _, tt := range tests {
+		if got := runSort(t, tt.input, tt.args...); got != tt.expected {
+			t.Errorf("sort %v: Expected %q but got %q", tt.args, tt.expected, got)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	input := "carol 35 paris\nalice 30 rome\nbob 4 oslo\n"
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-k", "2", "-n"}, "bob 4 oslo\nalice 30 rome\ncarol 35 paris\n"},
+		{[]string{"-k", "3"}, "bob 4 oslo\ncarol 35 paris\nalice 30 rome\n"},
+		{[]string{"-k", "2,2"}, "alice 30 rome\ncarol 35 paris\nbob 4 oslo\n"},
+		{[]string{"-k", "9"}, "alice 30 rome\nbob 4 oslo\ncarol 35 paris\n"},
+	}
+	for _, tt := range tests {
+		if got := runSort(t, input, tt.args...); got != tt.expected {
+			t.Errorf("sort %v: Expected %q but got %q", tt.args, tt.expected, got)
+		}
+	}
+}
+
+func TestKeyMissingField(t *testing.T) {
+	// The key of "x  " is the blanks after its first field, which sort
+	// after the empty key of "y".
+	if got, expected := runSort(t, "x  \ny\n", "-k", "2"), "y\nx  \n"; got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestMergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	os.WriteFile(a, []byte("delta\nalpha\n"), 0o644)
+	os.WriteFile(b, []byte("charlie\nbravo"), 0o644)
+	var stdout, stderr bytes.Buffer
+	run([]string{a, b}, nil, &stdout, &stderr)
+	if expected := "alpha\nbravo\ncharlie\ndelta\n"; stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-k", "0"}, strings.NewReader(""), &stdout, &stderr)
+	expected := "sort: invalid key specification: '0'\n"
+	if status != 2 || stderr.String() != expected {
+		t.Errorf("Expected %q (2) but got %q (%d)", expected, stderr.String(), status)
+	}
+}
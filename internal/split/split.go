@@ -0,0 +1,218 @@
+// Package split implements the functionality for the "split" Unix tool.
+package split
+
+import (
+	"bufio"   // Provides buffered reading and writing.
+	"errors"  // For defining the exhausted-suffixes error.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting error messages.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"strconv" // For parsing counts and sizes.
+	"strings" // For recognizing size suffixes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// errSuffixesExhausted reports that there are more pieces than names.
+var errSuffixesExhausted = errors.New("output file suffixes exhausted")
+
+// options holds the parsed command-line flags.
+type options struct {
+	lines        int64  // -l: lines per piece, when splitting by lines.
+	bytes        int64  // -b: bytes per piece, or 0 to split by lines.
+	suffixLength int    // -a: length of the suffixes.
+	numeric      bool   // -d: use numeric rather than alphabetic suffixes.
+	prefix       string // The operand naming the pieces, "x" by default.
+}
+
+// Run is the entry point for the split functionality.
+// It writes the input file (or stdin) to pieces named PREFIXaa, PREFIXab
+// and so on, of a fixed number of lines or bytes each, and returns the
+// exit status: 0 on success, 1 if the input could not be read or a piece
+// could not be written, or 2 for a usage error.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "split".
+	fset := flag.NewFlagSet("split", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-l" flag for the number of lines per piece.
+	fset.Int64Var(&opts.lines, "l", 1000, "put `NUMBER` lines per output file")
+	// Define the "-b" flag for the number of bytes per piece.
+	size := fset.String("b", "", "put `SIZE` bytes per output file, e.g. 512, 10K or 1M")
+	// Define the "-a" flag for the length of the suffixes.
+	fset.IntVar(&opts.suffixLength, "a", 2, "generate suffixes of length `N`")
+	// Define the "-d" flag to number the pieces.
+	fset.BoolVar(&opts.numeric, "d", false, "use numeric suffixes instead of alphabetic")
+	fset.Parse(args)
+
+	byLines := false
+	fset.Visit(func(f *flag.Flag) {
+		byLines = byLines || f.Name == "l"
+	})
+	if byLines && *size != "" {
+		cli.Fprintf(stderr, "split", "cannot split in more than one way")
+		return cli.ExitUsage
+	}
+	if opts.lines <= 0 {
+		cli.Fprintf(stderr, "split", "invalid number of lines: '%d'", opts.lines)
+		return cli.ExitUsage
+	}
+	if *size != "" {
+		var err error
+		if opts.bytes, err = parseSize(*size); err != nil {
+			cli.Fprintf(stderr, "split", "invalid number of bytes: '%s'", *size)
+			return cli.ExitUsage
+		}
+	}
+	if opts.suffixLength < 1 {
+		cli.Fprintf(stderr, "split", "invalid suffix length: '%d'", opts.suffixLength)
+		return cli.ExitUsage
+	}
+	if fset.NArg() > 2 {
+		cli.Fprintf(stderr, "split", "extra operand '%s'", fset.Arg(2))
+		return cli.ExitUsage
+	}
+
+	// The input defaults to stdin, and the prefix to "x".
+	file := "-"
+	opts.prefix = "x"
+	if fset.NArg() > 0 {
+		file = fset.Arg(0)
+	}
+	if fset.NArg() > 1 {
+		opts.prefix = fset.Arg(1)
+	}
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			cli.Fprintf(stderr, "split", "cannot open '%s' for reading: %v", file, cli.Unwrap(err))
+			return cli.ExitFailure
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if err := split(bufio.NewReader(r), &opts); err != nil {
+		cli.Fprintf(stderr, "split", "%v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// parseSize parses the argument of -b: a number of bytes, optionally
+// followed by K, M, G or T for powers of 1024, or KB, MB, GB or TB for
+// powers of 1000.
+func parseSize(arg string) (int64, error) {
+	digits := strings.TrimRight(arg, "KMGTkmgtB")
+	unit := strings.ToUpper(arg[len(digits):])
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, strconv.ErrSyntax
+	}
+	if unit == "" {
+		return n, nil
+	}
+	base := int64(1024)
+	if strings.HasSuffix(unit, "B") && len(unit) == 2 {
+		base = 1000
+		unit = unit[:1]
+	}
+	power := strings.Index("KMGT", unit) + 1
+	if len(unit) != 1 || power == 0 {
+		return 0, strconv.ErrSyntax
+	}
+	for ; power > 0; power-- {
+		if n > (1<<63-1)/base {
+			return 0, strconv.ErrRange
+		}
+		n *= base
+	}
+	return n, nil
+}
+
+// split copies r to successive pieces. A piece is only created once there
+// is data for it, so empty input creates no files.
+func split(r *bufio.Reader, opts *options) error {
+	for piece := 0; ; piece++ {
+		if _, err := r.Peek(1); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		name, err := pieceName(opts, piece)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, cli.Unwrap(err))
+		}
+		w := bufio.NewWriter(f)
+		if opts.bytes > 0 {
+			_, err = io.CopyN(w, r, opts.bytes)
+			if err == io.EOF {
+				err = nil // The last piece may be shorter.
+			}
+		} else {
+			err = copyLines(w, r, opts.lines)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, cli.Unwrap(err))
+		}
+	}
+}
+
+// copyLines copies up to n lines from r to w.
+func copyLines(w *bufio.Writer, r *bufio.Reader, n int64) error {
+	for ; n > 0; n-- {
+		// Long lines are copied piecewise until their newline is found.
+		for {
+			chunk, err := r.ReadSlice('\n')
+			if _, werr := w.Write(chunk); werr != nil {
+				return werr
+			}
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// pieceName returns the name of piece number n: the prefix followed by a
+// suffix such as "aa", "ab", ... or, with -d, "00", "01", ...
+func pieceName(opts *options, n int) (string, error) {
+	digits := "abcdefghijklmnopqrstuvwxyz"
+	if opts.numeric {
+		digits = "0123456789"
+	}
+	suffix := make([]byte, opts.suffixLength)
+	for i := len(suffix) - 1; i >= 0; i-- {
+		suffix[i] = digits[n%len(digits)]
+		n /= len(digits)
+	}
+	if n > 0 {
+		return "", errSuffixesExhausted
+	}
+	return opts.prefix + string(suffix), nil
+}
@@ -0,0 +1,107 @@
+package split
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// pieces returns the names and contents of the files in dir.
+func pieces(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	files := map[string]string{}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", entry.Name(), err)
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected map[string]string
+	}{
+		{[]string{"-l", "2"}, "1\n2\n3\n4\n5", map[string]string{"xaa": "1\n2\n", "xab": "3\n4\n", "xac": "5"}},
+		{[]string{"-l", "2"}, "1\n2\n", map[string]string{"xaa": "1\n2\n"}},
+		{[]string{"-b", "4"}, "abcdefghij", map[string]string{"xaa": "abcd", "xab": "efgh", "xac": "ij"}},
+		{[]string{"-b", "3", "-d", "-", "part."}, "abcdef", map[string]string{"part.00": "abc", "part.01": "def"}},
+		{[]string{"-a", "3", "-l", "1"}, "a\nb\n", map[string]string{"xaaa": "a\n", "xaab": "b\n"}},
+		{[]string{"-b", "1K"}, strings.Repeat("z", 1500), map[string]string{"xaa": strings.Repeat("z", 1024), "xab": strings.Repeat("z", 476)}},
+		{nil, "", map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Chdir(t.TempDir())
+		var stderr bytes.Buffer
+		if status := run(tt.args, strings.NewReader(tt.input), &stderr); status != 0 {
+			t.Fatalf("split %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		got := pieces(t, ".")
+		if len(got) != len(tt.expected) {
+			t.Errorf("split %v: Expected files %v but got %v", tt.args, tt.expected, got)
+			continue
+		}
+		for name, content := range tt.expected {
+			if got[name] != content {
+				t.Errorf("split %v: Expected %q in %s but got %q", tt.args, content, name, got[name])
+			}
+		}
+	}
+}
+
+func TestPieceName(t *testing.T) {
+	opts := &options{suffixLength: 2, prefix: "x"}
+	var names []string
+	for _, n := range []int{0, 1, 25, 26, 675} {
+		name, err := pieceName(opts, n)
+		if err != nil {
+			t.Fatalf("pieceName(%d): unexpected error %v", n, err)
+		}
+		names = append(names, name)
+	}
+	if expected := []string{"xaa", "xab", "xaz", "xba", "xzz"}; !slices.Equal(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+	if _, err := pieceName(opts, 676); err != errSuffixesExhausted {
+		t.Errorf("Expected %v but got %v", errSuffixesExhausted, err)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected int64
+	}{
+		{"100", 100},
+		{"2K", 2048},
+		{"1M", 1 << 20},
+		{"1MB", 1000000},
+		{"3g", 3 << 30},
+		{"0", 0},
+		{"K", 0},
+		{"5X", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.arg)
+		if tt.expected == 0 {
+			if err == nil {
+				t.Errorf("parseSize(%q): Expected an error but got %d", tt.arg, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("parseSize(%q): Expected %d but got %d (%v)", tt.arg, tt.expected, got, err)
+		}
+	}
+}
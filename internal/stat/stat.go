@@ -0,0 +1,256 @@
+// Package stat implements the functionality for the "stat" Unix tool.
+package stat
+
+import (
+	"bufio"   // For buffering the output.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting the metadata.
+	"io"      // Provides the writer abstraction used for output.
+	"os"      // For reading file metadata.
+	"os/user" // To look up user and group names.
+	"strconv" // For formatting numeric IDs and fields.
+	"strings" // For building custom-format output.
+	"time"    // For formatting timestamps.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"      // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/fileinfo" // Platform-specific file metadata.
+)
+
+// timeLayout is how timestamps are shown in the default layout and by
+// the %x, %y and %z directives.
+const timeLayout = "2006-01-02 15:04:05.000000000 -0700"
+
+// file is the metadata of one operand, as shown by stat.
+type file struct {
+	name   string      // The operand as given on the command line.
+	target string      // Where a symbolic link points, if name is one.
+	info   os.FileInfo // The metadata read for name.
+}
+
+// Run is the entry point for the stat functionality.
+// It prints the metadata of each file, either in the default layout or as
+// described by -c FORMAT, and returns the exit status: 0 on success, 1 if
+// any file could not be examined, or 2 for a usage error.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "stat".
+	fset := flag.NewFlagSet("stat", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-c" flag to print selected fields instead of the default layout.
+	format := fset.String("c", "", "use the specified `FORMAT` instead of the default layout")
+	// Define the "-L" flag to describe the targets of symbolic links.
+	dereference := fset.Bool("L", false, "follow symbolic links")
+	fset.Parse(args)
+
+	names := fset.Args()
+	if len(names) == 0 {
+		cli.Fprintf(stderr, "stat", "missing operand")
+		return cli.ExitUsage
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for _, name := range names {
+		f, err := statFile(name, *dereference)
+		if err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "stat", "cannot stat '%s': %v", name, cli.Unwrap(err))
+			status = cli.ExitFailure
+			continue
+		}
+		if *format != "" {
+			out.WriteString(expand(*format, f) + "\n")
+		} else {
+			writeDefault(out, f)
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "stat", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// statFile reads the metadata of name, of the link itself unless
+// dereference is set.
+func statFile(name string, dereference bool) (*file, error) {
+	stat := os.Lstat
+	if dereference {
+		stat = os.Stat
+	}
+	info, err := stat(name)
+	if err != nil {
+		return nil, err
+	}
+	f := &file{name: name, info: info}
+	if info.Mode()&os.ModeSymlink != 0 {
+		// An unreadable target is simply not shown.
+		f.target, _ = os.Readlink(name)
+	}
+	return f, nil
+}
+
+// writeDefault prints f in the multi-line layout used when no format is
+// given.
+func writeDefault(w io.Writer, f *file) {
+	info := f.info
+	uid, gid := owner(info)
+	dev := fileinfo.Device(info)
+	fmt.Fprintf(w, "  File: %s\n", quotedName(f))
+	fmt.Fprintf(w, "  Size: %-10d\tBlocks: %-10d IO Block: %-6d %s\n",
+		info.Size(), fileinfo.Blocks(info), fileinfo.BlockSize(info), fileType(info))
+	fmt.Fprintf(w, "Device: %-15s Inode: %-11d Links: %d\n",
+		fmt.Sprintf("%xh/%dd", dev, dev), fileinfo.Inode(info), fileinfo.Links(info))
+	fmt.Fprintf(w, "Access: (%04o/%s)  Uid: (%5s/%8s)   Gid: (%5s/%8s)\n",
+		fileinfo.Octal(info.Mode()), fileinfo.ModeString(info.Mode()),
+		uid, userName(uid), gid, groupName(gid))
+	fmt.Fprintf(w, "Access: %s\n", fileinfo.Atime(info).Format(timeLayout))
+	fmt.Fprintf(w, "Modify: %s\n", info.ModTime().Format(timeLayout))
+	fmt.Fprintf(w, "Change: %s\n", fileinfo.Ctime(info).Format(timeLayout))
+}
+
+// expand returns format with each %-directive replaced by the field of f
+// it names. Unknown directives are shown as '?', and a lone '%' at the end
+// of format is kept as it is.
+func expand(format string, f *file) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i == len(format)-1 {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		b.WriteString(directive(format[i], f))
+	}
+	return b.String()
+}
+
+// directive returns the field of f selected by the directive character c.
+func directive(c byte, f *file) string {
+	info := f.info
+	switch c {
+	case '%':
+		return "%"
+	case 'n':
+		return f.name
+	case 'N':
+		return quotedName(f)
+	case 's':
+		return strconv.FormatInt(info.Size(), 10)
+	case 'b':
+		return strconv.FormatInt(fileinfo.Blocks(info), 10)
+	case 'B':
+		return "512"
+	case 'o':
+		return strconv.FormatInt(fileinfo.BlockSize(info), 10)
+	case 'F':
+		return fileType(info)
+	case 'a':
+		return strconv.FormatUint(uint64(fileinfo.Octal(info.Mode())), 8)
+	case 'A':
+		return fileinfo.ModeString(info.Mode())
+	case 'i':
+		return strconv.FormatUint(fileinfo.Inode(info), 10)
+	case 'h':
+		return strconv.FormatUint(fileinfo.Links(info), 10)
+	case 'd':
+		return strconv.FormatUint(fileinfo.Device(info), 10)
+	case 'D':
+		return strconv.FormatUint(fileinfo.Device(info), 16)
+	case 'u':
+		uid, _ := owner(info)
+		return uid
+	case 'U':
+		uid, _ := owner(info)
+		return userName(uid)
+	case 'g':
+		_, gid := owner(info)
+		return gid
+	case 'G':
+		_, gid := owner(info)
+		return groupName(gid)
+	case 'x':
+		return fileinfo.Atime(info).Format(timeLayout)
+	case 'y':
+		return info.ModTime().Format(timeLayout)
+	case 'z':
+		return fileinfo.Ctime(info).Format(timeLayout)
+	case 'X':
+		return epoch(fileinfo.Atime(info))
+	case 'Y':
+		return epoch(info.ModTime())
+	case 'Z':
+		return epoch(fileinfo.Ctime(info))
+	default:
+		return "?"
+	}
+}
+
+// quotedName returns the name of f in quotes, followed by the target of
+// a symbolic link.
+func quotedName(f *file) string {
+	if f.target != "" {
+		return "'" + f.name + "' -> '" + f.target + "'"
+	}
+	return "'" + f.name + "'"
+}
+
+// fileType describes the kind of file info is, in the words stat uses.
+func fileType(info os.FileInfo) string {
+	mode := info.Mode()
+	switch {
+	case mode.IsRegular() && info.Size() == 0:
+		return "regular empty file"
+	case mode.IsRegular():
+		return "regular file"
+	case mode&os.ModeDir != 0:
+		return "directory"
+	case mode&os.ModeSymlink != 0:
+		return "symbolic link"
+	case mode&os.ModeCharDevice != 0:
+		return "character special file" // Checked before ModeDevice, which character devices also set.
+	case mode&os.ModeDevice != 0:
+		return "block special file"
+	case mode&os.ModeNamedPipe != 0:
+		return "fifo"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	default:
+		return "weird file"
+	}
+}
+
+// owner returns the numeric owner and group of info as strings, or "?"
+// for each where the platform does not record them.
+func owner(info os.FileInfo) (uid, gid string) {
+	id, gr, ok := fileinfo.Owner(info)
+	if !ok {
+		return "?", "?"
+	}
+	return strconv.Itoa(id), strconv.Itoa(gr)
+}
+
+// userName returns the name of the user with the given ID, or "UNKNOWN".
+func userName(uid string) string {
+	if usr, err := user.LookupId(uid); err == nil {
+		return usr.Username
+	}
+	return "UNKNOWN"
+}
+
+// groupName returns the name of the group with the given ID, or "UNKNOWN".
+func groupName(gid string) string {
+	if grp, err := user.LookupGroupId(gid); err == nil {
+		return grp.Name
+	}
+	return "UNKNOWN"
+}
+
+// epoch formats t as seconds since the Unix epoch.
+func epoch(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,80 @@
+package stat
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+func TestFormat(t *testing.T) {
+	path := testutil.TempFile(t, "data.txt", "hello\n")
+	mtime := time.Unix(1700000000, 0)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("Failed to set times on %s: %v", path, err)
+	}
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-c", "%n %s %F %Y %% %q", path}, &stdout, &stderr)
+	if status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	want := path + " 6 regular file 1700000000 % ?\n"
+	if stdout.String() != want {
+		t.Errorf("Expected %q but got %q", want, stdout.String())
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatalf("Failed to chmod %s: %v", path, err)
+	}
+	stdout.Reset()
+	run([]string{"-c", "%a %A %u", path}, &stdout, &stderr)
+	want = "640 -rw-r----- " + strconv.Itoa(os.Getuid()) + "\n"
+	if stdout.String() != want {
+		t.Errorf("Expected %q but got %q", want, stdout.String())
+	}
+}
+
+func TestDefault(t *testing.T) {
+	path := testutil.TempFile(t, "data.txt", "hello\n")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{path}, &stdout, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	prefixes := []string{"  File: '" + path + "'", "  Size: 6 ", "Device: ", "Access: (", "Access: ", "Modify: ", "Change: "}
+	if len(lines) != len(prefixes) {
+		t.Fatalf("Expected %d lines but got %q", len(prefixes), stdout.String())
+	}
+	for i, prefix := range prefixes {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("Expected line %d to start with %q but got %q", i+1, prefix, lines[i])
+		}
+	}
+	if !strings.HasSuffix(lines[1], " regular file") {
+		t.Errorf("Expected %q to end with the file type", lines[1])
+	}
+	if runtime.GOOS != "windows" && !strings.HasPrefix(lines[3], "Access: (0644/-rw-r--r--)") {
+		t.Errorf("Expected the permissions of %s but got %q", path, lines[3])
+	}
+}
+
+func TestMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{missing}, &stdout, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	want := "stat: cannot stat '" + missing + "': no such file or directory\n"
+	if runtime.GOOS != "windows" && stderr.String() != want {
+		t.Errorf("Expected %q but got %q", want, stderr.String())
+	}
+}
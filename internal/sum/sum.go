@@ -0,0 +1,202 @@
+// Package sum implements the functionality for the "sha256sum" and
+// "md5sum" Unix tools.
+package sum
+
+import (
+	"bufio"         // For reading checksum lists and buffering output.
+	"crypto/md5"    // For md5sum digests.
+	"crypto/sha256" // For sha256sum digests.
+	"encoding/hex"  // For printing and parsing digests.
+	"flag"          // Used to parse command-line flags.
+	"fmt"           // For formatting results and warnings.
+	"hash"          // For the interface shared by the digests.
+	"io"            // Provides the reader and writer abstractions used for input and output.
+	"os"            // For interacting with the file system and OS I/O.
+	"strings"       // For parsing checksum lines.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// algorithm is a digest computed by one of the tools.
+type algorithm struct {
+	tool string           // The name of the tool, used in diagnostics.
+	new  func() hash.Hash // Creates a hash computing the digest.
+	size int              // Length of the digest in bytes.
+}
+
+// The digests the tools compute.
+var (
+	sha256Algorithm = algorithm{tool: "sha256sum", new: sha256.New, size: sha256.Size}
+	md5Algorithm    = algorithm{tool: "md5sum", new: md5.New, size: md5.Size}
+)
+
+// RunSHA256 is the entry point for the sha256sum functionality.
+// It prints the SHA-256 digest of each file (or stdin), or with -c checks
+// the digests listed in the given files, and returns the exit status: 0
+// on success, or 1 if a file could not be read or a digest did not match.
+func RunSHA256(args []string) int {
+	return run(sha256Algorithm, args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// RunMD5 is the entry point for the md5sum functionality. It behaves like
+// RunSHA256, but with MD5 digests.
+func RunMD5(args []string) int {
+	return run(md5Algorithm, args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of RunSHA256 and RunMD5 using the provided
+// streams.
+func run(algo algorithm, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to the tool.
+	fset := flag.NewFlagSet(algo.tool, flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-c" flag to verify digests listed in files.
+	check := fset.Bool("c", false, "read checksums from the FILEs and check them")
+	fset.Parse(args)
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for _, file := range files {
+		var ok bool
+		if *check {
+			ok = checkList(algo, out, file, stdin, stderr)
+		} else {
+			ok = printDigest(algo, out, file, stdin, stderr)
+		}
+		if !ok {
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, algo.tool, "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// printDigest prints the digest of file followed by its name.
+func printDigest(algo algorithm, w *bufio.Writer, file string, stdin io.Reader, stderr io.Writer) bool {
+	sum, err := digest(algo, file, stdin)
+	if err != nil {
+		w.Flush()
+		cli.Fprintf(stderr, algo.tool, "%s: %v", file, cli.Unwrap(err))
+		return false
+	}
+	fmt.Fprintf(w, "%s  %s\n", sum, file)
+	return true
+}
+
+// digest returns the hex-encoded digest of file, or of stdin for "-".
+func digest(algo algorithm, file string, stdin io.Reader) (string, error) {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		r = f
+	}
+	h := algo.new()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkList verifies each "DIGEST  NAME" line of the checksum list in
+// file, printing "NAME: OK" or "NAME: FAILED" for each, and warns about
+// anything that went wrong. The result is false if any file was missing
+// or did not match, or if the list held no valid lines.
+func checkList(algo algorithm, w *bufio.Writer, file string, stdin io.Reader, stderr io.Writer) bool {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			w.Flush()
+			cli.Fprintf(stderr, algo.tool, "%s: %v", file, cli.Unwrap(err))
+			return false
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var valid, malformed, unreadable, mismatched int
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if want, name, ok := parseLine(algo, strings.TrimRight(line, "\r\n")); ok {
+				valid++
+				got, err := digest(algo, name, stdin)
+				switch {
+				case err != nil:
+					w.Flush()
+					cli.Fprintf(stderr, algo.tool, "%s: %v", name, cli.Unwrap(err))
+					fmt.Fprintf(w, "%s: FAILED open or read\n", name)
+					unreadable++
+				case !strings.EqualFold(got, want):
+					fmt.Fprintf(w, "%s: FAILED\n", name)
+					mismatched++
+				default:
+					fmt.Fprintf(w, "%s: OK\n", name)
+				}
+			} else {
+				malformed++
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			w.Flush()
+			cli.Fprintf(stderr, algo.tool, "%s: %v", file, cli.Unwrap(err))
+			return false
+		}
+	}
+
+	w.Flush()
+	if valid == 0 {
+		cli.Fprintf(stderr, algo.tool, "%s: no properly formatted checksum lines found", file)
+		return false
+	}
+	if malformed > 0 {
+		cli.Fprintf(stderr, algo.tool, "WARNING: %s improperly formatted", plural(malformed, "line is", "lines are"))
+	}
+	if unreadable > 0 {
+		cli.Fprintf(stderr, algo.tool, "WARNING: %s could not be read", plural(unreadable, "listed file", "listed files"))
+	}
+	if mismatched > 0 {
+		cli.Fprintf(stderr, algo.tool, "WARNING: %s did NOT match", plural(mismatched, "computed checksum", "computed checksums"))
+	}
+	return unreadable == 0 && mismatched == 0
+}
+
+// parseLine splits a checksum line into its digest and file name. The
+// name follows the digest after a space and a second space for text mode
+// or '*' for binary mode, which are treated alike.
+func parseLine(algo algorithm, line string) (sum, name string, ok bool) {
+	n := 2 * algo.size
+	if len(line) < n+3 || line[n] != ' ' || (line[n+1] != ' ' && line[n+1] != '*') {
+		return "", "", false
+	}
+	sum = line[:n]
+	if _, err := hex.DecodeString(sum); err != nil {
+		return "", "", false
+	}
+	return sum, line[n+2:], true
+}
+
+// plural formats n followed by the singular or plural form of a noun.
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
@@ -0,0 +1,88 @@
+package sum
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+const (
+	helloSHA256 = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+	helloMD5    = "b1946ac92492d2347c6235b4d2611184"
+	emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+func TestDigest(t *testing.T) {
+	path := testutil.TempFile(t, "hello.txt", "hello\n")
+	tests := []struct {
+		algo     algorithm
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{sha256Algorithm, []string{path}, "", helloSHA256 + "  " + path + "\n"},
+		{md5Algorithm, []string{path}, "", helloMD5 + "  " + path + "\n"},
+		{sha256Algorithm, nil, "hello\n", helloSHA256 + "  -\n"},
+		{sha256Algorithm, []string{path, "-"}, "", helloSHA256 + "  " + path + "\n" + emptySHA256 + "  -\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.algo, tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); status != 0 {
+			t.Fatalf("%s %v: Expected status 0 but got %d: %s", tt.algo.tool, tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("%s %v: Expected %q but got %q", tt.algo.tool, tt.args, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	good := testutil.TempFile(t, "good.txt", "hello\n")
+	bad := testutil.TempFile(t, "bad.txt", "changed\n")
+	missing := filepath.Join(t.TempDir(), "missing")
+	list := testutil.TempFile(t, "SHA256SUMS", helloSHA256+"  "+good+"\n"+
+		helloSHA256+" *"+bad+"\n"+
+		helloSHA256+"  "+missing+"\n"+
+		"not a checksum line\n")
+
+	var stdout, stderr bytes.Buffer
+	if status := run(sha256Algorithm, []string{"-c", list}, strings.NewReader(""), &stdout, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	expected := good + ": OK\n" + bad + ": FAILED\n" + missing + ": FAILED open or read\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+	for _, warning := range []string{
+		"WARNING: 1 line is improperly formatted",
+		"WARNING: 1 listed file could not be read",
+		"WARNING: 1 computed checksum did NOT match",
+	} {
+		if !strings.Contains(stderr.String(), warning) {
+			t.Errorf("Expected %q in %q", warning, stderr.String())
+		}
+	}
+
+	// A list read from stdin whose entries all match succeeds.
+	stdout.Reset()
+	stderr.Reset()
+	if status := run(md5Algorithm, []string{"-c"}, strings.NewReader(helloMD5+"  "+good+"\n"), &stdout, &stderr); status != 0 {
+		t.Errorf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if expected := good + ": OK\n"; stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestCheckMalformed(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run(md5Algorithm, []string{"-c"}, strings.NewReader("garbage\n"), &stdout, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	if expected := "md5sum: -: no properly formatted checksum lines found\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
@@ -0,0 +1,121 @@
+package tail
+
+import (
+	"bufio"   // Provides buffered writing.
+	"context" // For stopping when interrupted.
+	"io"      // For copying appended data.
+	"os"      // For watching and reopening the followed files.
+	"time"    // For the polling interval.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// pollInterval is how often followed files are checked for new data.
+var pollInterval = time.Second
+
+// follower is a file that -f keeps printing as it grows.
+type follower struct {
+	name   string   // The name the file was opened by, used to detect rotation.
+	file   *os.File // The open file being read.
+	offset int64    // How much of the file has been printed.
+}
+
+// follow polls the followers until ctx is done, copying data appended to
+// them to w. A file that shrinks is taken to have been truncated and is
+// printed again from its start; a name that now refers to another file is
+// taken to have been rotated and is reopened. With headers, output is
+// introduced by the file's name whenever it switches to another file.
+// The result is false if reading failed.
+func follow(ctx context.Context, w *bufio.Writer, followers []*follower, headers bool, stderr io.Writer) bool {
+	defer func() {
+		for _, fl := range followers {
+			fl.file.Close()
+		}
+	}()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	ok := true
+	// The last file printed before following began is the current one.
+	current := followers[len(followers)-1]
+	for {
+		select {
+		case <-ctx.Done():
+			return ok
+		case <-ticker.C:
+		}
+		for _, fl := range followers {
+			data, polled := fl.poll(stderr)
+			if !polled {
+				ok = false
+			}
+			if len(data) == 0 {
+				continue
+			}
+			if headers && fl != current {
+				w.WriteString("\n==> " + fl.name + " <==\n")
+			}
+			current = fl
+			w.Write(data)
+			if err := w.Flush(); err != nil {
+				cli.Fprintf(stderr, "tail", "write error: %v", err)
+				return false
+			}
+		}
+	}
+}
+
+// poll returns what has been appended to the file since it was last read,
+// first reopening it if it was rotated or rewinding it if it was
+// truncated. The result is false if the file could not be read.
+func (fl *follower) poll(stderr io.Writer) ([]byte, bool) {
+	info, err := os.Stat(fl.name)
+	if err != nil {
+		// The name may briefly be missing while the file is rotated.
+		return nil, true
+	}
+	current, err := fl.file.Stat()
+	if err != nil {
+		cli.Fprintf(stderr, "tail", "cannot follow '%s': %v", fl.name, cli.Unwrap(err))
+		return nil, false
+	}
+
+	switch {
+	case !os.SameFile(info, current):
+		// Keep what was still written to the old file, then switch over.
+		rest, ok := fl.read(stderr)
+		if !ok {
+			return rest, false
+		}
+		f, err := os.Open(fl.name)
+		if err != nil {
+			return rest, true // Try again once the new file can be opened.
+		}
+		fl.file.Close()
+		fl.file, fl.offset = f, 0
+		cli.Fprintf(stderr, "tail", "'%s' has been replaced; following new file", fl.name)
+		data, ok := fl.read(stderr)
+		return append(rest, data...), ok
+	case info.Size() < fl.offset:
+		cli.Fprintf(stderr, "tail", "%s: file truncated", fl.name)
+		if _, err := fl.file.Seek(0, io.SeekStart); err != nil {
+			cli.Fprintf(stderr, "tail", "cannot follow '%s': %v", fl.name, cli.Unwrap(err))
+			return nil, false
+		}
+		fl.offset = 0
+	case info.Size() == fl.offset:
+		return nil, true // Nothing new.
+	}
+	return fl.read(stderr)
+}
+
+// read returns everything from the file's offset to its current end.
+func (fl *follower) read(stderr io.Writer) ([]byte, bool) {
+	data, err := io.ReadAll(fl.file)
+	fl.offset += int64(len(data))
+	if err != nil {
+		cli.Fprintf(stderr, "tail", "error reading '%s': %v", fl.name, cli.Unwrap(err))
+		return data, false
+	}
+	return data, true
+}
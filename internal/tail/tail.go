@@ -0,0 +1,327 @@
+// Package tail implements the functionality for the "tail" Unix tool.
+package tail
+
+import (
+	"bufio"     // Provides buffered reading and writing.
+	"context"   // For stopping -f when interrupted.
+	"flag"      // Used to parse command-line flags.
+	"fmt"       // For formatting error messages.
+	"io"        // Provides the reader and writer abstractions used for input and output.
+	"os"        // For interacting with the file system and OS I/O.
+	"os/signal" // For ending -f cleanly on an interrupt.
+	"strconv"   // For parsing line and byte counts.
+	"strings"   // For recognizing the "+N" form of counts.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// stdinLabel names standard input in headers and diagnostics.
+const stdinLabel = "standard input"
+
+// blockSize is how much of a file is read at a time when scanning it
+// backwards for line breaks.
+const blockSize = 8192
+
+// options holds the parsed command-line flags.
+type options struct {
+	count     int64 // -n or -c: how many lines or bytes to print.
+	bytes     bool  // -c: count bytes rather than lines.
+	fromStart bool  // "+N": print from line or byte N onwards instead of the last N.
+	follow    bool  // -f: keep printing data appended to the files.
+}
+
+// Run is the entry point for the tail functionality.
+// It prints the last part of each file (or stdin), then with -f keeps
+// printing what is appended to the files until interrupted. It returns the
+// exit status: 0 on success, or 1 if any input could not be read.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "tail".
+	fset := flag.NewFlagSet("tail", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-n" flag for the number of lines to print.
+	lines := fset.String("n", "10", "print the last `N` lines, or from line N onwards with +N")
+	// Define the "-c" flag for the number of bytes to print.
+	bytes := fset.String("c", "", "print the last `N` bytes, or from byte N onwards with +N")
+	var opts options
+	// Define the "-f" flag to keep printing data as the files grow.
+	fset.BoolVar(&opts.follow, "f", false, "output appended data as the file grows")
+	fset.Parse(args)
+
+	var err error
+	if *bytes != "" {
+		opts.bytes = true
+		opts.count, opts.fromStart, err = parseCount(*bytes)
+		if err != nil {
+			cli.Fprintf(stderr, "tail", "invalid number of bytes: '%s'", *bytes)
+			return cli.ExitUsage
+		}
+	} else {
+		opts.count, opts.fromStart, err = parseCount(*lines)
+		if err != nil {
+			cli.Fprintf(stderr, "tail", "invalid number of lines: '%s'", *lines)
+			return cli.ExitUsage
+		}
+	}
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	var followers []*follower
+	for i, file := range files {
+		// With several inputs, each one is introduced by a header.
+		if len(files) > 1 {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			name := file
+			if file == "-" {
+				name = stdinLabel
+			}
+			out.WriteString("==> " + name + " <==\n")
+		}
+		fl, err := tailFile(out, file, stdin, &opts)
+		if err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "tail", "%v", err)
+			status = cli.ExitFailure
+		}
+		if fl != nil {
+			followers = append(followers, fl)
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "tail", "write error: %v", err)
+		return cli.ExitFailure
+	}
+
+	// Keep printing the named files as they grow, until interrupted.
+	if len(followers) > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if !follow(ctx, out, followers, len(files) > 1, stderr) {
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// parseCount parses the argument of -n or -c: a plain count selects the
+// end of the input, while a leading '+' selects where printing starts.
+func parseCount(arg string) (n int64, fromStart bool, err error) {
+	if strings.HasPrefix(arg, "+") {
+		fromStart = true
+		arg = arg[1:]
+	} else {
+		// A leading '-' means the same as no sign at all.
+		arg = strings.TrimPrefix(arg, "-")
+	}
+	n, err = strconv.ParseInt(arg, 10, 64)
+	if err == nil && n < 0 {
+		err = strconv.ErrRange
+	}
+	return n, fromStart, err
+}
+
+// tailFile copies the end of the named file, or of stdin for "-", to w.
+// With -f, a named file is left open and returned as a follower, ready to
+// print whatever is appended to it next; stdin is never followed.
+func tailFile(w *bufio.Writer, file string, stdin io.Reader, opts *options) (*follower, error) {
+	if file == "-" {
+		if err := tail(w, stdin, opts); err != nil {
+			return nil, fmt.Errorf("error reading '%s': %v", stdinLabel, err)
+		}
+		return nil, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open '%s' for reading: %v", file, cli.Unwrap(err))
+	}
+	if err := tail(w, f, opts); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error reading '%s': %v", file, cli.Unwrap(err))
+	}
+	if !opts.follow {
+		f.Close()
+		return nil, nil
+	}
+	// Everything up to the current offset has been printed.
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("cannot follow '%s': %v", file, cli.Unwrap(err))
+	}
+	return &follower{name: file, file: f, offset: offset}, nil
+}
+
+// tail copies the selected part of r to w. Regular files are read
+// backwards from their end, so only the part that is printed is read;
+// anything else, such as a pipe, is read through once while keeping just
+// the most recent lines or bytes.
+func tail(w *bufio.Writer, r io.Reader, opts *options) error {
+	if opts.fromStart {
+		return copyFrom(w, r, opts)
+	}
+	if f, ok := r.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			return tailSeekable(w, f, info.Size(), opts)
+		}
+	}
+	if opts.bytes {
+		return tailBytes(w, r, opts.count)
+	}
+	return tailLines(w, r, opts.count)
+}
+
+// copyFrom skips to line or byte opts.count (counting from 1) of r and
+// copies the rest to w.
+func copyFrom(w *bufio.Writer, r io.Reader, opts *options) error {
+	skip := max(opts.count-1, 0) // "+0" means the same as "+1".
+	reader := bufio.NewReader(r)
+	if opts.bytes {
+		if _, err := io.CopyN(io.Discard, reader, skip); err != nil {
+			if err == io.EOF {
+				return nil // Shorter inputs print nothing.
+			}
+			return err
+		}
+	} else {
+		for ; skip > 0; skip-- {
+			// Skip long lines piecewise until their newline is found.
+			_, err := reader.ReadSlice('\n')
+			for err == bufio.ErrBufferFull {
+				_, err = reader.ReadSlice('\n')
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err := reader.WriteTo(w)
+	return err
+}
+
+// tailSeekable copies the end of the regular file f, which is size bytes
+// long, to w, seeking past everything before the part that is printed.
+func tailSeekable(w *bufio.Writer, f *os.File, size int64, opts *options) error {
+	// Respect the current offset, in case stdin is a partly read file.
+	start, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	offset := max(size-opts.count, start)
+	if !opts.bytes {
+		if offset, err = lastLinesOffset(f, start, size, opts.count); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// lastLinesOffset scans f backwards from end, one block at a time, and
+// returns the offset at which its last n lines begin, or start if the
+// region from start to end holds no more than n lines. A final line
+// without a trailing newline counts as a line.
+func lastLinesOffset(f *os.File, start, end, n int64) (int64, error) {
+	if n == 0 {
+		return end, nil
+	}
+	buf := make([]byte, blockSize)
+	pos := end
+	for pos > start {
+		size := min(blockSize, pos-start)
+		pos -= size
+		if _, err := f.Seek(pos, io.SeekStart); err != nil {
+			return 0, err
+		}
+		if _, err := io.ReadFull(f, buf[:size]); err != nil {
+			return 0, err
+		}
+		for i := size - 1; i >= 0; i-- {
+			// The newline ending the last line does not start a new one.
+			if buf[i] != '\n' || pos+i == end-1 {
+				continue
+			}
+			if n--; n == 0 {
+				return pos + i + 1, nil
+			}
+		}
+	}
+	return start, nil
+}
+
+// tailLines copies the last n lines of r to w, holding only those lines
+// in memory: a ring buffer keeps the most recent n lines read so far.
+func tailLines(w *bufio.Writer, r io.Reader, n int64) error {
+	if n == 0 {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+	reader := bufio.NewReader(r)
+	var ring [][]byte
+	next := 0 // Index of the oldest line once the ring is full.
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if int64(len(ring)) < n {
+				ring = append(ring, line)
+			} else {
+				ring[next] = line
+				next = (next + 1) % len(ring)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Print from the oldest line, wrapping around the end of the ring.
+	for _, line := range ring[next:] {
+		w.Write(line)
+	}
+	for _, line := range ring[:next] {
+		w.Write(line)
+	}
+	return nil
+}
+
+// tailBytes copies the last n bytes of r to w, holding at most about
+// twice that many bytes in memory.
+func tailBytes(w *bufio.Writer, r io.Reader, n int64) error {
+	var window []byte
+	chunk := make([]byte, 32*1024)
+	for {
+		k, err := r.Read(chunk)
+		window = append(window, chunk[:k]...)
+		// Drop bytes that can no longer be among the last n.
+		if int64(len(window)) > n {
+			window = window[int64(len(window))-n:]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(window)
+	return err
+}
@@ -0,0 +1,200 @@
+package tail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+// numbered returns lines "1\n" through "n\n".
+func numbered(n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		sb.WriteString(strconv.Itoa(i) + "\n")
+	}
+	return sb.String()
+}
+
+// runTail runs tail with args, once over content as stdin and once over a
+// file holding content, and checks that both paths agree.
+func runTail(t *testing.T, content string, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if status := run(args, strings.NewReader(content), &stdout, &stderr); status != 0 {
+		t.Fatalf("tail %v on stdin exited with %d: %s", args, status, stderr.String())
+	}
+	piped := stdout.String()
+
+	stdout.Reset()
+	path := testutil.TempFile(t, "input.txt", content)
+	if status := run(append(args, path), nil, &stdout, &stderr); status != 0 {
+		t.Fatalf("tail %v on a file exited with %d: %s", args, status, stderr.String())
+	}
+	if stdout.String() != piped {
+		t.Errorf("tail %v: Expected file output %q to match stdin output %q", args, stdout.String(), piped)
+	}
+	return piped
+}
+
+func TestLastLines(t *testing.T) {
+	long := numbered(5000) // Spans several blocks of the backward scan.
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{nil, numbered(20), strings.TrimPrefix(numbered(20), numbered(10))},
+		{[]string{"-n", "3"}, numbered(20), "18\n19\n20\n"},
+		{[]string{"-n", "-3"}, numbered(20), "18\n19\n20\n"},
+		{[]string{"-n", "5"}, numbered(2), "1\n2\n"},
+		{[]string{"-n", "0"}, numbered(2), ""},
+		{[]string{"-n", "2"}, "a\nb\nc", "b\nc"},
+		{[]string{"-n", "1"}, "a\n\n\n", "\n"},
+		{[]string{"-n", "2"}, "", ""},
+		{[]string{"-n", "2"}, long, "4999\n5000\n"},
+		{[]string{"-n", "4990"}, long, strings.TrimPrefix(long, numbered(10))},
+	}
+	for _, tt := range tests {
+		if got := runTail(t, tt.input, tt.args...); got != tt.expected {
+			t.Errorf("tail %v: Expected %q but got %q", tt.args, tt.expected, got)
+		}
+	}
+}
+
+func TestFromLine(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-n", "+3"}, "3\n4\n5\n"},
+		{[]string{"-n", "+1"}, numbered(5)},
+		{[]string{"-n", "+0"}, numbered(5)},
+		{[]string{"-n", "+9"}, ""},
+		{[]string{"-c", "+7"}, "4\n5\n"},
+	}
+	for _, tt := range tests {
+		if got := runTail(t, numbered(5), tt.args...); got != tt.expected {
+			t.Errorf("tail %v: Expected %q but got %q", tt.args, tt.expected, got)
+		}
+	}
+}
+
+func TestLastBytes(t *testing.T) {
+	tests := []struct {
+		count    string
+		expected string
+	}{
+		{"3", "ef\n"},
+		{"0", ""},
+		{"100", "abc\ndef\n"},
+	}
+	for _, tt := range tests {
+		if got := runTail(t, "abc\ndef\n", "-c", tt.count); got != tt.expected {
+			t.Errorf("tail -c %s: Expected %q but got %q", tt.count, tt.expected, got)
+		}
+	}
+}
+
+func TestMultipleFiles(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "1\n2\n3\n")
+	b := testutil.TempFile(t, "b.txt", "x\n")
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-n", "2", a, b}, nil, &stdout, &stderr)
+	expected := "==> " + a + " <==\n2\n3\n\n==> " + b + " <==\nx\n"
+	if stdout.String() != expected || status != 0 {
+		t.Errorf("Expected %q (0) but got %q (%d)", expected, stdout.String(), status)
+	}
+}
+
+func TestInvalidCount(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-n", "x"}, strings.NewReader(""), &stdout, &stderr)
+	expected := "tail: invalid number of lines: 'x'\n"
+	if status != 2 || stderr.String() != expected {
+		t.Errorf("Expected %q (2) but got %q (%d)", expected, stderr.String(), status)
+	}
+}
+
+// syncBuffer is a bytes.Buffer that can be written and read concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor polls buf until it equals expected, failing after a timeout.
+func waitFor(t *testing.T, buf *syncBuffer, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %q but got %q", expected, buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	pollInterval = 5 * time.Millisecond
+	path := testutil.TempFile(t, "log.txt", "1\n2\n")
+	var stdout, stderr syncBuffer
+	out := bufio.NewWriter(&stdout)
+	fl, err := tailFile(out, path, nil, &options{count: 10, follow: true})
+	if err != nil || fl == nil {
+		t.Fatalf("tailFile returned %v, %v", fl, err)
+	}
+	out.Flush()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() { done <- follow(ctx, out, []*follower{fl}, false, &stderr) }()
+	defer func() {
+		cancel()
+		if !<-done {
+			t.Errorf("Expected follow to succeed, stderr: %q", stderr.String())
+		}
+	}()
+
+	// Appended data is printed as it arrives.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	f.WriteString("3\n")
+	f.Close()
+	waitFor(t, &stdout, "1\n2\n3\n")
+
+	// A truncated file is printed again from its start.
+	if err := os.WriteFile(path, []byte("a\n"), 0o644); err != nil {
+		t.Fatalf("Failed to rewrite fixture: %v", err)
+	}
+	waitFor(t, &stdout, "1\n2\n3\na\n")
+
+	// A rotated file is replaced by the new file of the same name.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Failed to rotate fixture: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write new fixture: %v", err)
+	}
+	waitFor(t, &stdout, "1\n2\n3\na\nnew\n")
+}
@@ -0,0 +1,106 @@
+// Package tee implements the functionality for the "tee" Unix tool.
+package tee
+
+import (
+	"flag"      // Used to parse command-line flags.
+	"io"        // Provides the reader and writer abstractions used for input and output.
+	"os"        // For interacting with the file system and OS I/O.
+	"os/signal" // For ignoring interrupts with -i.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// Run is the entry point for the tee functionality.
+// It copies stdin to stdout and to each named file, and returns the exit
+// status: 0 on success, or 1 if any file could not be opened or written.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "tee".
+	fset := flag.NewFlagSet("tee", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-a" flag to append to the files rather than overwrite them.
+	appendMode := fset.Bool("a", false, "append to the given FILEs, do not overwrite")
+	// Define the "-i" flag to keep copying when interrupted.
+	ignoreInterrupts := fset.Bool("i", false, "ignore interrupt signals")
+	fset.Parse(args)
+
+	if *ignoreInterrupts {
+		signal.Ignore(os.Interrupt)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if *appendMode {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	// A file that cannot be opened is reported, and the others still get
+	// their copy.
+	status := cli.ExitSuccess
+	outputs := []*output{{name: "standard output", w: stdout}}
+	for _, name := range fset.Args() {
+		f, err := os.OpenFile(name, flags, 0o666)
+		if err != nil {
+			cli.Fprintf(stderr, "tee", "%s: %v", name, cli.Unwrap(err))
+			status = cli.ExitFailure
+			continue
+		}
+		outputs = append(outputs, &output{name: name, w: f, file: f})
+	}
+
+	if !copyTo(outputs, stdin, stderr) {
+		status = cli.ExitFailure
+	}
+	for _, out := range outputs {
+		if out.file == nil {
+			continue
+		}
+		if err := out.file.Close(); err != nil && !out.failed {
+			cli.Fprintf(stderr, "tee", "%s: %v", out.name, cli.Unwrap(err))
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// output is one of the destinations tee copies to.
+type output struct {
+	name   string    // Name used in diagnostics.
+	w      io.Writer // Where the copy is written.
+	file   *os.File  // The file behind w, to be closed; nil for stdout.
+	failed bool      // Whether a write failed, after which w is skipped.
+}
+
+// copyTo copies r to every output until r is exhausted or no output is
+// left. An output that fails to be written is reported and dropped, and
+// the others carry on. The result is false if anything failed.
+func copyTo(outputs []*output, r io.Reader, stderr io.Writer) bool {
+	ok := true
+	active := len(outputs)
+	buf := make([]byte, 32*1024)
+	for active > 0 {
+		n, err := r.Read(buf)
+		for _, out := range outputs {
+			if out.failed || n == 0 {
+				continue
+			}
+			if _, werr := out.w.Write(buf[:n]); werr != nil {
+				cli.Fprintf(stderr, "tee", "%s: %v", out.name, cli.Unwrap(werr))
+				out.failed = true
+				active--
+				ok = false
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cli.Fprintf(stderr, "tee", "read error: %v", err)
+			return false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,98 @@
+package tee
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// readFile returns the content of path, failing the test if it cannot be
+// read.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestCopies(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	if err := os.WriteFile(second, []byte("old content\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", second, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	input := "hello\nworld\n"
+	if status := run([]string{first, second}, strings.NewReader(input), &stdout, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if stdout.String() != input {
+		t.Errorf("Expected %q but got %q", input, stdout.String())
+	}
+	for _, path := range []string{first, second} {
+		if got := readFile(t, path); got != input {
+			t.Errorf("Expected %q in %s but got %q", input, path, got)
+		}
+	}
+
+	// With -a, the files keep what they had.
+	stdout.Reset()
+	if status := run([]string{"-a", first, second}, strings.NewReader("again\n"), &stdout, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	for _, path := range []string{first, second} {
+		if got, expected := readFile(t, path), input+"again\n"; got != expected {
+			t.Errorf("Expected %q in %s but got %q", expected, path, got)
+		}
+	}
+}
+
+func TestOpenFailure(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good")
+	bad := filepath.Join(dir, "missing", "bad")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{bad, good}, strings.NewReader("data\n"), &stdout, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	if got := readFile(t, good); got != "data\n" {
+		t.Errorf("Expected %q in %s but got %q", "data\n", good, got)
+	}
+	if stdout.String() != "data\n" {
+		t.Errorf("Expected %q but got %q", "data\n", stdout.String())
+	}
+	expected := "tee: " + bad + ": no such file or directory\n"
+	if runtime.GOOS != "windows" && stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
+
+// failingWriter is an io.Writer whose writes all fail.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestWriteFailure(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "copy")
+	input := strings.Repeat("data\n", 20000)
+	var stderr bytes.Buffer
+	if status := run([]string{file}, strings.NewReader(input), failingWriter{}, &stderr); status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	// The failed destination is reported once, and the file still gets
+	// all of the input.
+	if expected := "tee: standard output: broken pipe\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+	if got := readFile(t, file); got != input {
+		t.Errorf("Expected %d bytes in %s but got %d", len(input), file, len(got))
+	}
+}
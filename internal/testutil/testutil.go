@@ -0,0 +1,29 @@
+// Package testutil provides helpers shared by the tests of the tools.
+package testutil
+
+import (
+	"os"            // For writing the fixtures.
+	"path/filepath" // For building fixture paths.
+	"testing"       // For failing the calling test.
+)
+
+// TempFile creates a file called name holding content in a fresh
+// temporary directory and returns its path.
+func TempFile(t testing.TB, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	WriteFile(t, path, content, 0o644)
+	return path
+}
+
+// WriteFile writes content to path with the permissions perm, creating
+// any missing parent directories first.
+func WriteFile(t testing.TB, path, content string, perm os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create the directory of %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,142 @@
+// Package touch implements the functionality for the "touch" Unix tool.
+package touch
+
+import (
+	"errors"  // For recognizing missing files and describing bad stamps.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting error messages.
+	"io"      // Provides the writer abstraction used for diagnostics.
+	"io/fs"   // For the missing-file error.
+	"os"      // For creating files and changing their times.
+	"strings" // For splitting the seconds off a -t stamp.
+	"time"    // For parsing and representing timestamps.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"  // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/date" // For parsing -d dates the way date does.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	noCreate   bool      // -c: do not create missing files.
+	accessOnly bool      // -a: change only the access time.
+	modOnly    bool      // -m: change only the modification time.
+	time       time.Time // -t or -d: the time to set instead of the current time.
+}
+
+// Run is the entry point for the touch functionality.
+// It updates the access and modification times of each file, creating
+// empty files for names that do not exist, and returns the exit status:
+// 0 on success, 1 if any file could not be touched, or 2 on invalid
+// options.
+func Run(args []string) int {
+	return run(args, os.Stderr, time.Now)
+}
+
+// run performs the actual work of Run, reporting errors to stderr and
+// taking the current time from now.
+func run(args []string, stderr io.Writer, now func() time.Time) int {
+	// Create a new FlagSet for parsing command-line options specific to "touch".
+	fset := flag.NewFlagSet("touch", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-c" flag to leave missing files alone.
+	fset.BoolVar(&opts.noCreate, "c", false, "do not create any files")
+	// Define the "-a" flag to change only the access time.
+	fset.BoolVar(&opts.accessOnly, "a", false, "change only the access time")
+	// Define the "-m" flag to change only the modification time.
+	fset.BoolVar(&opts.modOnly, "m", false, "change only the modification time")
+	// Define the "-t" flag to use a POSIX timestamp instead of the current time.
+	stamp := fset.String("t", "", "use `[[CC]YY]MMDDhhmm[.ss]` instead of the current time")
+	// Define the "-d" flag to use a date string instead of the current time.
+	dateArg := fset.String("d", "", "parse `DATE` (such as 2006-01-02 15:04:05) and use it instead of the current time")
+	fset.Parse(args)
+
+	if fset.NArg() == 0 {
+		cli.Fprintf(stderr, "touch", "missing file operand")
+		return cli.ExitUsage
+	}
+
+	var err error
+	switch {
+	case *stamp != "" && *dateArg != "":
+		cli.Fprintf(stderr, "touch", "cannot specify times from more than one source")
+		return cli.ExitUsage
+	case *stamp != "":
+		if opts.time, err = parseStamp(*stamp, now()); err != nil {
+			cli.Fprintf(stderr, "touch", "invalid date format '%s'", *stamp)
+			return cli.ExitUsage
+		}
+	case *dateArg != "":
+		if opts.time, err = date.Parse(*dateArg, now(), time.Local); err != nil {
+			cli.Fprintf(stderr, "touch", "invalid date format '%s'", *dateArg)
+			return cli.ExitUsage
+		}
+	default:
+		opts.time = now()
+	}
+
+	status := cli.ExitSuccess
+	for _, file := range fset.Args() {
+		if err := touch(file, &opts); err != nil {
+			cli.Fprintf(stderr, "touch", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	return status
+}
+
+// touch sets the times of file as the options require, first creating it
+// if it does not exist and creating is allowed.
+func touch(file string, opts *options) error {
+	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		if opts.noCreate {
+			return nil // With -c, missing files are silently skipped.
+		}
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE, 0o666)
+		if err != nil {
+			return fmt.Errorf("cannot touch '%s': %v", file, cli.Unwrap(err))
+		}
+		f.Close()
+	}
+
+	// A zero time leaves the corresponding file time unchanged; -a and -m
+	// together are the same as neither.
+	atime, mtime := opts.time, opts.time
+	if opts.accessOnly && !opts.modOnly {
+		mtime = time.Time{}
+	}
+	if opts.modOnly && !opts.accessOnly {
+		atime = time.Time{}
+	}
+	if err := os.Chtimes(file, atime, mtime); err != nil {
+		return fmt.Errorf("setting times of '%s': %v", file, cli.Unwrap(err))
+	}
+	return nil
+}
+
+// parseStamp parses a -t timestamp of the form [[CC]YY]MMDDhhmm[.ss] in
+// the local time zone. Without a year, the year of now is used; a
+// two-digit year YY means 19YY for 69 to 99 and 20YY otherwise.
+func parseStamp(stamp string, now time.Time) (time.Time, error) {
+	digits, seconds, hasSeconds := strings.Cut(stamp, ".")
+	var layout string
+	switch len(digits) {
+	case 8:
+		digits = fmt.Sprintf("%04d", now.Year()) + digits
+		layout = "200601021504"
+	case 10:
+		layout = "0601021504"
+	case 12:
+		layout = "200601021504"
+	default:
+		return time.Time{}, errors.New("invalid timestamp length")
+	}
+	if hasSeconds {
+		if len(seconds) != 2 {
+			return time.Time{}, errors.New("invalid seconds")
+		}
+		digits += "." + seconds
+		layout += ".05"
+	}
+	return time.ParseInLocation(layout, digits, time.Local)
+}
@@ -0,0 +1,112 @@
+package touch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fixedNow is the current time seen by the tests.
+var fixedNow = time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local)
+
+// touchRun runs touch with args at fixedNow and returns its status and
+// diagnostics.
+func touchRun(args ...string) (int, string) {
+	var stderr bytes.Buffer
+	status := run(args, &stderr, func() time.Time { return fixedNow })
+	return status, stderr.String()
+}
+
+// modTime returns the modification time of path.
+func modTime(t *testing.T, path string) time.Time {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", path, err)
+	}
+	return info.ModTime()
+}
+
+func TestCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.txt")
+	if status, stderr := touchRun(path); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected the file to be created: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("Expected an empty file but got %d bytes", info.Size())
+	}
+	if !info.ModTime().Equal(fixedNow) {
+		t.Errorf("Expected %v but got %v", fixedNow, info.ModTime())
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.txt")
+	if err := os.WriteFile(path, []byte("keep"), 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local)
+	os.Chtimes(path, old, old)
+
+	touchRun(path)
+	if got := modTime(t, path); !got.Equal(fixedNow) {
+		t.Errorf("Expected %v but got %v", fixedNow, got)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "keep" {
+		t.Errorf("Expected the contents to be kept but got %q", data)
+	}
+
+	// -a leaves the modification time alone.
+	os.Chtimes(path, old, old)
+	touchRun("-a", path)
+	if got := modTime(t, path); !got.Equal(old) {
+		t.Errorf("Expected %v but got %v", old, got)
+	}
+}
+
+func TestNoCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	if status, stderr := touchRun("-c", path); status != 0 || stderr != "" {
+		t.Errorf("Expected status 0 and no output but got %d: %q", status, stderr)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the file not to be created, but stat returned %v", err)
+	}
+}
+
+func TestExplicitTimes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f")
+	tests := []struct {
+		args     []string
+		expected time.Time
+	}{
+		{[]string{"-t", "202001021530"}, time.Date(2020, 1, 2, 15, 30, 0, 0, time.Local)},
+		{[]string{"-t", "9912312359.59"}, time.Date(1999, 12, 31, 23, 59, 59, 0, time.Local)},
+		{[]string{"-t", "03041200"}, time.Date(2024, 3, 4, 12, 0, 0, 0, time.Local)},
+		{[]string{"-d", "2021-06-07 08:09:10"}, time.Date(2021, 6, 7, 8, 9, 10, 0, time.Local)},
+		{[]string{"-d", "2021-06-07"}, time.Date(2021, 6, 7, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		if status, stderr := touchRun(append(tt.args, path)...); status != 0 {
+			t.Errorf("touch %v: Expected status 0 but got %d: %s", tt.args, status, stderr)
+			continue
+		}
+		if got := modTime(t, path); !got.Equal(tt.expected) {
+			t.Errorf("touch %v: Expected %v but got %v", tt.args, tt.expected, got)
+		}
+	}
+}
+
+func TestInvalidStamp(t *testing.T) {
+	status, stderr := touchRun("-t", "2020", "f")
+	expected := "touch: invalid date format '2020'\n"
+	if status != 2 || stderr != expected {
+		t.Errorf("Expected %q (2) but got %q (%d)", expected, stderr, status)
+	}
+}
@@ -0,0 +1,226 @@
+// Package tr implements the functionality for the "tr" Unix tool.
+package tr
+
+import (
+	"bufio"   // Provides buffered writing.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting error messages.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For the standard streams.
+	"strconv" // For quoting sets in error messages.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	delete  bool // -d: delete the characters of SET1.
+	squeeze bool // -s: squeeze runs of a repeated character into one.
+}
+
+// Run is the entry point for the tr functionality.
+// It copies stdin to stdout, translating, deleting or squeezing the
+// characters of SET1 (and SET2), and returns the exit status: 0 on
+// success, 1 on a read or write error, or 2 on invalid operands.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "tr".
+	fs := flag.NewFlagSet("tr", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	var opts options
+	// Define the "-d" flag to delete characters instead of translating them.
+	fs.BoolVar(&opts.delete, "d", false, "delete characters in SET1, do not translate")
+	// Define the "-s" flag to squeeze repeated characters.
+	fs.BoolVar(&opts.squeeze, "s", false, "replace each sequence of a repeated character in the last SET with one")
+	fs.Parse(args)
+
+	// Translating, and deleting then squeezing, take two sets; deleting
+	// takes one; squeezing takes one, or two to translate first.
+	wantSets := 2
+	switch {
+	case opts.delete && !opts.squeeze:
+		wantSets = 1
+	case opts.squeeze && !opts.delete && fs.NArg() < 2:
+		wantSets = 1
+	}
+	if fs.NArg() < wantSets {
+		cli.Fprintf(stderr, "tr", "missing operand")
+		return cli.ExitUsage
+	}
+	if fs.NArg() > wantSets {
+		cli.Fprintf(stderr, "tr", "extra operand '%s'", fs.Arg(wantSets))
+		return cli.ExitUsage
+	}
+
+	set1, err := expand(fs.Arg(0))
+	if err != nil {
+		cli.Fprintf(stderr, "tr", "%v", err)
+		return cli.ExitUsage
+	}
+	var set2 []byte
+	if wantSets == 2 {
+		if set2, err = expand(fs.Arg(1)); err != nil {
+			cli.Fprintf(stderr, "tr", "%v", err)
+			return cli.ExitUsage
+		}
+	}
+
+	// Build one table per operation, indexed by byte value.
+	var t tables
+	switch {
+	case opts.delete:
+		t.mark(&t.delete, set1)
+		if opts.squeeze {
+			t.mark(&t.squeeze, set2)
+		}
+	case wantSets == 2:
+		if len(set2) == 0 {
+			cli.Fprintf(stderr, "tr", "when not truncating set1, string2 must be non-empty")
+			return cli.ExitUsage
+		}
+		t.translate = translation(set1, set2)
+		if opts.squeeze {
+			t.mark(&t.squeeze, set2)
+		}
+	default:
+		t.mark(&t.squeeze, set1)
+	}
+
+	out := bufio.NewWriter(stdout)
+	if err := t.apply(out, stdin); err != nil {
+		out.Flush()
+		cli.Fprintf(stderr, "tr", "read error: %v", err)
+		return cli.ExitFailure
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "tr", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// tables holds what tr does to each byte value.
+type tables struct {
+	translate *[256]byte // What each byte becomes, or nil to keep bytes as they are.
+	delete    [256]bool  // Bytes to drop.
+	squeeze   [256]bool  // Bytes whose repeats are squeezed into one.
+}
+
+// mark sets the entries of table for every byte in set.
+func (t *tables) mark(table *[256]bool, set []byte) {
+	for _, b := range set {
+		table[b] = true
+	}
+}
+
+// apply streams r to w a block at a time, deleting, translating and then
+// squeezing bytes. Squeezing looks at the output, so runs are squeezed
+// even when they span two blocks.
+func (t *tables) apply(w *bufio.Writer, r io.Reader) error {
+	buf := make([]byte, 32*1024)
+	last := -1 // The last byte written, or -1 before any.
+	for {
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			if t.delete[b] {
+				continue
+			}
+			if t.translate != nil {
+				b = t.translate[b]
+			}
+			if t.squeeze[b] && int(b) == last {
+				continue
+			}
+			w.WriteByte(b)
+			last = int(b)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// translation maps each byte of set1 to the byte at the same position in
+// set2, which is extended with its last byte as needed. When a byte
+// appears more than once in set1, its last mapping wins.
+func translation(set1, set2 []byte) *[256]byte {
+	var table [256]byte
+	for i := range table {
+		table[i] = byte(i)
+	}
+	for i, b := range set1 {
+		table[b] = set2[min(i, len(set2)-1)]
+	}
+	return &table
+}
+
+// expand turns a set operand into the bytes it lists: escapes such as
+// \n, \t, \\ and octal \NNN stand for single bytes, and X-Y stands for
+// every byte from X to Y.
+func expand(set string) ([]byte, error) {
+	// First resolve escapes, remembering which bytes were escaped so that
+	// an escaped '-' does not form a range.
+	var chars []byte
+	var escaped []bool
+	for i := 0; i < len(set); i++ {
+		c, isEscape := set[i], false
+		if c == '\\' && i+1 < len(set) {
+			isEscape = true
+			i++
+			switch e := set[i]; {
+			case e >= '0' && e <= '7':
+				// Up to three octal digits.
+				v, j := 0, i
+				for ; j < len(set) && j < i+3 && set[j] >= '0' && set[j] <= '7'; j++ {
+					v = v*8 + int(set[j]-'0')
+				}
+				if v > 0xff {
+					return nil, fmt.Errorf("invalid octal escape in %s", strconv.Quote(set))
+				}
+				c, i = byte(v), j-1
+			case e == 'n':
+				c = '\n'
+			case e == 't':
+				c = '\t'
+			case e == 'r':
+				c = '\r'
+			case e == 'a':
+				c = '\a'
+			case e == 'b':
+				c = '\b'
+			case e == 'f':
+				c = '\f'
+			case e == 'v':
+				c = '\v'
+			default:
+				c = e // Any other escaped byte stands for itself, such as \\ or \-.
+			}
+		}
+		chars = append(chars, c)
+		escaped = append(escaped, isEscape)
+	}
+
+	var out []byte
+	for i := 0; i < len(chars); i++ {
+		if i+2 < len(chars) && chars[i+1] == '-' && !escaped[i+1] {
+			lo, hi := chars[i], chars[i+2]
+			if lo > hi {
+				return nil, fmt.Errorf("range-endpoints of '%c-%c' are in reverse collating sequence order", lo, hi)
+			}
+			for c := int(lo); c <= int(hi); c++ {
+				out = append(out, byte(c))
+			}
+			i += 2
+			continue
+		}
+		out = append(out, chars[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package tr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// runTr runs tr with args over input and returns its output, diagnostics
+// and status.
+func runTr(input string, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(input), &stdout, &stderr)
+	return stdout.String(), stderr.String(), status
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{[]string{"a-z", "A-Z"}, "Hello, World!\n", "HELLO, WORLD!\n"},
+		{[]string{"abc", "x"}, "aabbcc-d", "xxxxxx-d"},
+		{[]string{"a-c", "1-3"}, "abcd", "123d"},
+		{[]string{`\n`, " "}, "one\ntwo\n", "one two "},
+		{[]string{`\-a`, "_b"}, "a-b", "b_b"},
+		{[]string{`\101`, "a"}, "ABA", "aBa"},
+	}
+	for _, tt := range tests {
+		got, stderr, status := runTr(tt.input, tt.args...)
+		if got != tt.expected || status != 0 {
+			t.Errorf("tr %q: Expected %q (0) but got %q (%d) %s", tt.args, tt.expected, got, status, stderr)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{[]string{"-d", "0-9"}, "a1b22c333\n", "abc\n"},
+		{[]string{"-d", "aeiou"}, "education", "dctn"},
+		{[]string{"-d", "-s", "0-9", " "}, "a 1 1  b", "a b"},
+	}
+	for _, tt := range tests {
+		got, stderr, status := runTr(tt.input, tt.args...)
+		if got != tt.expected || status != 0 {
+			t.Errorf("tr %q: Expected %q (0) but got %q (%d) %s", tt.args, tt.expected, got, status, stderr)
+		}
+	}
+}
+
+func TestSqueeze(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{[]string{"-s", " "}, "a   b  c\n", "a b c\n"},
+		{[]string{"-s", "a-z"}, "aabbccdd  xx", "abcd  x"},
+		{[]string{"-s", "a-z", "A-Z"}, "aab bbc", "AB BC"},
+	}
+	for _, tt := range tests {
+		got, stderr, status := runTr(tt.input, tt.args...)
+		if got != tt.expected || status != 0 {
+			t.Errorf("tr %q: Expected %q (0) but got %q (%d) %s", tt.args, tt.expected, got, status, stderr)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stderr string
+	}{
+		{[]string{"abc"}, "tr: missing operand\n"},
+		{[]string{"-d", "a", "b"}, "tr: extra operand 'b'\n"},
+		{[]string{"z-a", "x"}, "tr: range-endpoints of 'z-a' are in reverse collating sequence order\n"},
+		{[]string{"a", ""}, "tr: when not truncating set1, string2 must be non-empty\n"},
+	}
+	for _, tt := range tests {
+		_, stderr, status := runTr("", tt.args...)
+		if stderr != tt.stderr || status != 2 {
+			t.Errorf("tr %q: Expected %q (2) but got %q (%d)", tt.args, tt.stderr, stderr, status)
+		}
+	}
+}
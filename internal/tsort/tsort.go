@@ -0,0 +1,236 @@
+// Package tsort implements the functionality for the "tsort" Unix tool.
+package tsort
+
+import (
+	"bufio"  // Provides buffered scanning of whitespace-separated tokens.
+	"flag"   // Used to parse command-line flags.
+	"fmt"    // For formatted I/O operations.
+	"io"     // Provides the reader and writer abstractions used for input and output.
+	"os"     // For interacting with the file system and OS I/O.
+	"slices" // Provides helpers for searching slices.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// graph is a directed graph of nodes read from tsort input.
+// An edge a -> b means that a must be printed before b.
+type graph struct {
+	nodes []string            // Nodes in order of first appearance, for stable output.
+	succ  map[string][]string // Successors of each node.
+	pred  map[string][]string // Predecessors of each node, used to trace loops.
+}
+
+// newGraph returns an empty graph ready to receive edges.
+func newGraph() *graph {
+	return &graph{
+		succ: make(map[string][]string),
+		pred: make(map[string][]string),
+	}
+}
+
+// addNode registers a node if it has not been seen before.
+func (g *graph) addNode(name string) {
+	if _, ok := g.succ[name]; ok {
+		return
+	}
+	g.nodes = append(g.nodes, name)
+	g.succ[name] = nil
+	g.pred[name] = nil
+}
+
+// addEdge records that from must come before to.
+// A pair of identical nodes only registers the node, as in coreutils.
+func (g *graph) addEdge(from, to string) {
+	g.addNode(from)
+	g.addNode(to)
+	if from == to {
+		return
+	}
+	g.succ[from] = append(g.succ[from], to)
+	g.pred[to] = append(g.pred[to], from)
+}
+
+// Run is the entry point for the tsort functionality.
+// It returns the exit status: 0 on success, 1 if the input is malformed,
+// unreadable, or contains a loop.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "tsort".
+	fs := flag.NewFlagSet("tsort", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	fs.Parse(args)
+
+	if fs.NArg() > 1 {
+		cli.Fprintf(stderr, "tsort", "extra operand '%s'", fs.Arg(1))
+		return 1
+	}
+
+	// Read from the named file, or from standard input if none (or "-") is given.
+	name := "-"
+	input := stdin
+	if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		name = fs.Arg(0)
+		file, err := os.Open(name)
+		if err != nil {
+			cli.Fprintf(stderr, "tsort", "%v", err)
+			return 1
+		}
+		defer file.Close()
+		input = file
+	}
+
+	g, err := readGraph(input)
+	if err != nil {
+		cli.Fprintf(stderr, "tsort", "%s: %v", name, err)
+		return 1
+	}
+
+	order, loops := sortGraph(g)
+
+	// Report every loop that had to be broken, one node per line like coreutils.
+	for _, loop := range loops {
+		cli.Fprintf(stderr, "tsort", "%s: input contains a loop:", name)
+		for _, node := range loop {
+			cli.Fprintf(stderr, "tsort", "%s", node)
+		}
+	}
+
+	// Print the (possibly best-effort) ordering.
+	w := bufio.NewWriter(stdout)
+	for _, node := range order {
+		fmt.Fprintln(w, node)
+	}
+	if err := w.Flush(); err != nil {
+		cli.Fprintf(stderr, "tsort", "write error: %v", err)
+		return 1
+	}
+
+	if len(loops) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// readGraph reads whitespace-separated pairs of nodes from r and builds a graph.
+// It returns an error if the input holds an odd number of tokens.
+func readGraph(r io.Reader) (*graph, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+
+	g := newGraph()
+	var pending string // First token of a pair still waiting for its partner.
+	havePending := false
+	for scanner.Scan() {
+		if !havePending {
+			pending = scanner.Text()
+			havePending = true
+			continue
+		}
+		g.addEdge(pending, scanner.Text())
+		havePending = false
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if havePending {
+		return nil, fmt.Errorf("input contains an odd number of tokens")
+	}
+	return g, nil
+}
+
+// sortGraph orders the nodes of g topologically using Kahn's algorithm.
+// When the remaining nodes all sit on or behind a loop, the loop is recorded
+// and broken by releasing one of its nodes, so a best-effort ordering of
+// every node is always returned.
+func sortGraph(g *graph) (order []string, loops [][]string) {
+	// Count the unsatisfied predecessors of every node.
+	indegree := make(map[string]int, len(g.nodes))
+	for _, node := range g.nodes {
+		indegree[node] = len(g.pred[node])
+	}
+
+	// Seed the queue with all nodes that have no predecessors.
+	var queue []string
+	for _, node := range g.nodes {
+		if indegree[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	done := make(map[string]bool, len(g.nodes))
+	for len(order) < len(g.nodes) {
+		if len(queue) == 0 {
+			// Every remaining node waits on another remaining node: find a loop.
+			loop := findLoop(g, done)
+			loops = append(loops, loop)
+			// Break the loop by releasing its first node regardless of predecessors.
+			indegree[loop[0]] = 0
+			queue = append(queue, loop[0])
+		}
+
+		node := queue[0]
+		queue = queue[1:]
+		if done[node] {
+			continue
+		}
+		done[node] = true
+		order = append(order, node)
+
+		for _, next := range g.succ[node] {
+			if done[next] {
+				continue
+			}
+			indegree[next]--
+			if indegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return order, loops
+}
+
+// findLoop returns the nodes of a loop among the nodes not yet emitted.
+// Every such node has at least one pending predecessor, so walking
+// predecessors from any of them must eventually revisit a node.
+func findLoop(g *graph, done map[string]bool) []string {
+	// Start from the first pending node in input order.
+	var start string
+	for _, node := range g.nodes {
+		if !done[node] {
+			start = node
+			break
+		}
+	}
+
+	seen := make(map[string]int) // Position of each visited node in path.
+	var path []string
+	node := start
+	for {
+		if at, ok := seen[node]; ok {
+			// The path was walked backwards; collect it in edge order.
+			loop := make([]string, 0, len(path)-at)
+			for i := len(path) - 1; i >= at; i-- {
+				loop = append(loop, path[i])
+			}
+			// Rotate the loop so it starts at its earliest node in input order.
+			for _, first := range g.nodes {
+				if i := slices.Index(loop, first); i >= 0 {
+					return append(loop[i:], loop[:i]...)
+				}
+			}
+			return loop
+		}
+		seen[node] = len(path)
+		path = append(path, node)
+		for _, prev := range g.pred[node] {
+			if !done[prev] {
+				node = prev
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package tsort
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunDAG(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	input := "shirt tie\ntie jacket\nsocks shoes\npants shoes\npants belt\nbelt jacket\nshirt belt\n"
+
+	code := run(nil, strings.NewReader(input), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0 but got %d (stderr %q)", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	pos := make(map[string]int)
+	for i, line := range lines {
+		pos[line] = i
+	}
+	if len(pos) != 7 {
+		t.Fatalf("Expected 7 nodes but got %q", lines)
+	}
+
+	edges := [][2]string{
+		{"shirt", "tie"}, {"tie", "jacket"}, {"socks", "shoes"}, {"pants", "shoes"},
+		{"pants", "belt"}, {"belt", "jacket"}, {"shirt", "belt"},
+	}
+	for _, e := range edges {
+		if pos[e[0]] > pos[e[1]] {
+			t.Errorf("Expected %q before %q in %q", e[0], e[1], lines)
+		}
+	}
+}
+
+func TestRunCycle(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	input := "a b\nb c\nc a\nc d\n"
+
+	code := run(nil, strings.NewReader(input), &stdout, &stderr)
+	if code == 0 {
+		t.Errorf("Expected non-zero exit code for cyclic input")
+	}
+	if !strings.Contains(stderr.String(), "input contains a loop") {
+		t.Errorf("Expected loop report on stderr but got %q", stderr.String())
+	}
+
+	expected := "a\nb\nc\nd\n"
+	if got := stdout.String(); got != expected {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestRunOddTokens(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	if code := run(nil, strings.NewReader("a b c"), &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 but got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "odd number of tokens") {
+		t.Errorf("Expected odd token error but got %q", stderr.String())
+	}
+}
@@ -0,0 +1,183 @@
+// Package unexpand implements the functionality for the "unexpand" Unix
+// tool.
+package unexpand
+
+import (
+	"bufio"        // Provides buffered reading and writing.
+	"flag"         // Used to parse command-line flags.
+	"fmt"          // For formatting error messages.
+	"io"           // Provides the reader and writer abstractions used for input and output.
+	"os"           // For interacting with the file system and OS I/O.
+	"strings"      // For building the converted lines.
+	"unicode/utf8" // For counting columns in characters rather than bytes.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli"    // Shared conventions for reporting errors.
+	"github.com/drunkleen/unix-tools-go/internal/expand" // For parsing tab stops the way expand does.
+)
+
+// Run is the entry point for the unexpand functionality.
+// It copies each file (or stdin) with leading blanks, or with -a all runs
+// of blanks, turned into tabs where they reach a tab stop. It returns the
+// exit status: 0 on success, 1 if any file could not be read, or 2 for
+// invalid tab stops.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "unexpand".
+	fset := flag.NewFlagSet("unexpand", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	// Define the "-a" flag to convert all blanks, not just leading ones.
+	all := fset.Bool("a", false, "convert all blanks, instead of just initial blanks")
+	// Define the "-t" flag to set the tab stops.
+	list := fset.String("t", "8", "have tabs `N` characters apart, or use the comma-separated LIST of positions (implies -a)")
+	fset.Parse(args)
+
+	stops, err := expand.ParseTabStops(*list)
+	if err != nil {
+		cli.Fprintf(stderr, "unexpand", "%v", err)
+		return cli.ExitUsage
+	}
+	// As with other implementations, choosing the tab stops implies -a.
+	fset.Visit(func(f *flag.Flag) {
+		if f.Name == "t" {
+			*all = true
+		}
+	})
+
+	// Read standard input when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	status := cli.ExitSuccess
+	for _, file := range files {
+		if err := unexpandFile(out, file, stdin, stops, *all); err != nil {
+			out.Flush()
+			cli.Fprintf(stderr, "unexpand", "%v", err)
+			status = cli.ExitFailure
+		}
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "unexpand", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// unexpandFile copies the named file, or stdin for "-", to w with its
+// blanks converted to tabs.
+func unexpandFile(w *bufio.Writer, file string, stdin io.Reader, stops expand.TabStops, all bool) error {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+		}
+		defer f.Close()
+		r = f
+	}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			w.WriteString(unexpandLine(line, stops, all))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+		}
+	}
+}
+
+// unexpandLine returns line with each run of blanks that reaches a tab
+// stop replaced by a tab. A lone space that follows a non-blank character
+// and reaches a stop is kept unless more blanks follow, since a tab would
+// not save anything. Unless all is set, only the blanks before the first
+// non-blank character are converted.
+func unexpandLine(line string, stops expand.TabStops, all bool) string {
+	var b strings.Builder
+	var pending strings.Builder // Blanks seen since the last stop or non-blank.
+	// lone is set when a single space has just reached a stop. Like GNU
+	// unexpand, it becomes a tab if more blanks follow before the last
+	// stop, and stays a space otherwise.
+	lone := false
+	// afterBlank is set when the previous character was a converted
+	// blank, and, as in GNU unexpand, at the start of the line.
+	afterBlank := true
+	col := 0
+	leading := true
+	for len(line) > 0 {
+		r, size := utf8.DecodeRuneInString(line)
+		raw := line[:size]
+		line = line[size:]
+		if (r == ' ' || r == '\t') && (leading || all) {
+			if lone {
+				if _, ok := stops.Next(col); ok {
+					b.WriteByte('\t')
+				} else {
+					b.WriteByte(' ')
+				}
+				lone = false
+			}
+			if r == ' ' {
+				col++
+			} else if next, ok := stops.Next(col); ok {
+				col = next
+			} else {
+				// Past the last stop, tabs are kept as they are.
+				b.WriteString(pending.String() + "\t")
+				pending.Reset()
+				col++
+				afterBlank = true
+				continue
+			}
+			pending.WriteRune(r)
+			if stops.IsStop(col) {
+				if pending.String() == " " && !afterBlank {
+					lone = true
+				} else {
+					b.WriteByte('\t')
+				}
+				pending.Reset()
+			}
+			afterBlank = true
+			continue
+		}
+		afterBlank = false
+
+		if lone {
+			b.WriteByte(' ')
+			lone = false
+		}
+		b.WriteString(pending.String())
+		pending.Reset()
+		switch r {
+		case '\b':
+			col = max(col-1, 0)
+		case '\t':
+			if next, ok := stops.Next(col); ok {
+				col = next
+			} else {
+				col++
+			}
+		default:
+			col++
+		}
+		if r != ' ' && r != '\t' {
+			leading = false
+		}
+		b.WriteString(raw)
+	}
+	if lone {
+		b.WriteByte(' ')
+	}
+	b.WriteString(pending.String())
+	return b.String()
+}
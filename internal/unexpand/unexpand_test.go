@@ -0,0 +1,49 @@
+package unexpand
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUnexpand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{nil, "        x\n", "\tx\n"},
+		{nil, "          x       y\n", "\t  x       y\n"},
+		{nil, "  \t x\n", "\t x\n"},
+		{[]string{"-a"}, "abc     d       e\n", "abc\td\te\n"},
+		{[]string{"-a"}, "abcdefg x\n", "abcdefg x\n"}, // A single space is not worth a tab.
+		{[]string{"-t", "4"}, "    a   b\n", "\ta\tb\n"},
+		{[]string{"-t", "2,5"}, "     a      b\n", "\t\ta      b\n"},
+		// A single space reaching a stop becomes a tab only if more blanks follow.
+		{[]string{"-t", "4"}, " :x  \n", " :x\t \n"},
+		{[]string{"-t", "4"}, "abc d\n", "abc d\n"},
+		{[]string{"-t", "2"}, "a   b\n", "a\t\tb\n"},
+		{[]string{"-t", "2,5"}, "a aa  \n", "a aa  \n"},
+		{[]string{"-t", "1"}, "a  b\n", "a\t\tb\n"},
+		{[]string{"-t", "1"}, " b\n", "\tb\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if status := run(tt.args, strings.NewReader(tt.input), &stdout, &stderr); status != 0 {
+			t.Fatalf("unexpand %v: Expected status 0 but got %d: %s", tt.args, status, stderr.String())
+		}
+		if stdout.String() != tt.expected {
+			t.Errorf("unexpand %v: Expected %q but got %q", tt.args, tt.expected, stdout.String())
+		}
+	}
+}
+
+func TestInvalidTabStops(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-t", "0"}, strings.NewReader(""), &stdout, &stderr); status != 2 {
+		t.Errorf("Expected status 2 but got %d", status)
+	}
+	if expected := "unexpand: tab size cannot be 0\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
@@ -0,0 +1,139 @@
+// Package uniq implements the functionality for the "uniq" Unix tool.
+package uniq
+
+import (
+	"bufio"   // Provides buffered reading and writing.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatting counts and error messages.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"strings" // For comparing lines without regard to case.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	count      bool // -c: prefix lines with the number of occurrences.
+	repeated   bool // -d: print only lines that are repeated.
+	unique     bool // -u: print only lines that are not repeated.
+	ignoreCase bool // -i: compare lines without regard to case.
+}
+
+// Run is the entry point for the uniq functionality.
+// It copies INPUT (or stdin) to OUTPUT (or stdout), collapsing runs of
+// adjacent identical lines into one, and returns the exit status: 0 on
+// success, 1 if the input or output could not be used, or 2 on invalid
+// usage.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "uniq".
+	fset := flag.NewFlagSet("uniq", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-c" flag to prefix lines with their number of occurrences.
+	fset.BoolVar(&opts.count, "c", false, "prefix lines by the number of occurrences")
+	// Define the "-d" flag to print only duplicated lines.
+	fset.BoolVar(&opts.repeated, "d", false, "only print duplicate lines, one for each group")
+	// Define the "-u" flag to print only lines that are not duplicated.
+	fset.BoolVar(&opts.unique, "u", false, "only print unique lines")
+	// Define the "-i" flag to ignore case when comparing.
+	fset.BoolVar(&opts.ignoreCase, "i", false, "ignore differences in case when comparing")
+	fset.Parse(args)
+
+	if fset.NArg() > 2 {
+		cli.Fprintf(stderr, "uniq", "extra operand '%s'", fset.Arg(2))
+		return cli.ExitUsage
+	}
+
+	// The optional operands name the input and output files.
+	in := stdin
+	if name := fset.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			cli.Fprintf(stderr, "uniq", "%s: %v", name, cli.Unwrap(err))
+			return cli.ExitFailure
+		}
+		defer f.Close()
+		in = f
+	}
+	out := stdout
+	if name := fset.Arg(1); name != "" && name != "-" {
+		f, err := os.Create(name)
+		if err != nil {
+			cli.Fprintf(stderr, "uniq", "%s: %v", name, cli.Unwrap(err))
+			return cli.ExitFailure
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+	if err := uniq(w, in, &opts); err != nil {
+		w.Flush()
+		cli.Fprintf(stderr, "uniq", "%v", cli.Unwrap(err))
+		return cli.ExitFailure
+	}
+	if err := w.Flush(); err != nil {
+		cli.Fprintf(stderr, "uniq", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitSuccess
+}
+
+// uniq copies r to w, printing each run of adjacent equal lines once, as
+// the options select. Only neighbouring lines are compared, so the input
+// is usually sorted first.
+func uniq(w *bufio.Writer, r io.Reader, opts *options) error {
+	reader := bufio.NewReader(r)
+	var current string // First line of the current run.
+	run := 0           // Length of the current run.
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimSuffix(line, "\n")
+			if run > 0 && equal(current, line, opts) {
+				run++
+			} else {
+				if run > 0 {
+					emit(w, current, run, opts)
+				}
+				current, run = line, 1
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if run > 0 {
+		emit(w, current, run, opts)
+	}
+	return nil
+}
+
+// equal reports whether two lines belong to the same run.
+func equal(a, b string, opts *options) bool {
+	if opts.ignoreCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// emit prints line, which occurred n times in a row, unless -d or -u
+// exclude it.
+func emit(w *bufio.Writer, line string, n int, opts *options) {
+	if (opts.repeated && n == 1) || (opts.unique && n > 1) {
+		return
+	}
+	if opts.count {
+		fmt.Fprintf(w, "%7d ", n)
+	}
+	w.WriteString(line + "\n")
+}
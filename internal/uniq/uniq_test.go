@@ -0,0 +1,45 @@
+package uniq
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModes(t *testing.T) {
+	input := "a\na\nb\nA\na\nc\nc\nc"
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "a\nb\nA\na\nc\n"},
+		{[]string{"-c"}, "      2 a\n      1 b\n      1 A\n      1 a\n      3 c\n"},
+		{[]string{"-d"}, "a\nc\n"},
+		{[]string{"-u"}, "b\nA\na\n"},
+		{[]string{"-i"}, "a\nb\nA\nc\n"},
+		{[]string{"-i", "-c"}, "      2 a\n      1 b\n      2 A\n      3 c\n"},
+		{[]string{"-d", "-u"}, ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, strings.NewReader(input), &stdout, &stderr)
+		if stdout.String() != tt.expected || status != 0 {
+			t.Errorf("uniq %v: Expected %q (0) but got %q (%d)", tt.args, tt.expected, stdout.String(), status)
+		}
+	}
+}
+
+func TestInputAndOutputFiles(t *testing.T) {
+	dir := t.TempDir()
+	in, out := filepath.Join(dir, "in"), filepath.Join(dir, "out")
+	os.WriteFile(in, []byte("x\nx\ny\n"), 0o644)
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{in, out}, nil, &stdout, &stderr); status != 0 {
+		t.Fatalf("Expected status 0 but got %d: %s", status, stderr.String())
+	}
+	if data, _ := os.ReadFile(out); string(data) != "x\ny\n" {
+		t.Errorf("Expected %q but got %q", "x\ny\n", data)
+	}
+}
@@ -0,0 +1,217 @@
+// Package wc implements the functionality for the "wc" Unix tool.
+package wc
+
+import (
+	"bufio"   // Provides buffered writing of the results.
+	"flag"    // Used to parse command-line flags.
+	"fmt"     // For formatted output of the counts.
+	"io"      // Provides the reader and writer abstractions used for input and output.
+	"os"      // For interacting with the file system and OS I/O.
+	"strconv" // For measuring the width of the largest count.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	lines bool // -l: print the newline count.
+	words bool // -w: print the word count.
+	chars bool // -m: print the character count.
+	bytes bool // -c: print the byte count.
+}
+
+// counts holds the tallies for one input.
+type counts struct {
+	lines, words, chars, bytes int64
+}
+
+// add adds the tallies of other to c.
+func (c *counts) add(other counts) {
+	c.lines += other.lines
+	c.words += other.words
+	c.chars += other.chars
+	c.bytes += other.bytes
+}
+
+// result is the counts of one input together with the name printed
+// after them.
+type result struct {
+	name   string
+	counts counts
+}
+
+// Run is the entry point for the wc functionality.
+// It prints newline, word, character and byte counts for each file (or
+// stdin) and returns the exit status: 0 on success, or 1 if any input
+// could not be read.
+func Run(args []string) int {
+	return run(args, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "wc".
+	fset := flag.NewFlagSet("wc", flag.ExitOnError)
+	fset.SetOutput(stderr)
+	var opts options
+	// Define the "-l" flag to print newline counts.
+	fset.BoolVar(&opts.lines, "l", false, "print the newline counts")
+	// Define the "-w" flag to print word counts.
+	fset.BoolVar(&opts.words, "w", false, "print the word counts")
+	// Define the "-m" flag to print character counts.
+	fset.BoolVar(&opts.chars, "m", false, "print the character counts")
+	// Define the "-c" flag to print byte counts.
+	fset.BoolVar(&opts.bytes, "c", false, "print the byte counts")
+	fset.Parse(args)
+	// Without any flags, print the classic three counts.
+	if !opts.lines && !opts.words && !opts.chars && !opts.bytes {
+		opts.lines, opts.words, opts.bytes = true, true, true
+	}
+
+	// Count standard input, unnamed, when no files are given.
+	files := fset.Args()
+	if len(files) == 0 {
+		files = []string{""}
+	}
+
+	// Every input is counted before anything is printed, so the columns
+	// can be sized to fit the largest number.
+	status := cli.ExitSuccess
+	var results []result
+	var total counts
+	for _, file := range files {
+		c, err := countFile(file, stdin)
+		if err != nil {
+			cli.Fprintf(stderr, "wc", "%v", err)
+			status = cli.ExitFailure
+			continue
+		}
+		results = append(results, result{name: file, counts: c})
+		total.add(c)
+	}
+	if len(files) > 1 {
+		results = append(results, result{name: "total", counts: total})
+	}
+
+	out := bufio.NewWriter(stdout)
+	width := columnWidth(total, &opts)
+	for _, r := range results {
+		printCounts(out, r, width, &opts)
+	}
+	if err := out.Flush(); err != nil {
+		cli.Fprintf(stderr, "wc", "write error: %v", err)
+		return cli.ExitFailure
+	}
+	return status
+}
+
+// countFile counts the named file, or stdin when the name is "" or "-".
+func countFile(file string, stdin io.Reader) (counts, error) {
+	if file == "" || file == "-" {
+		return count(stdin)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return counts{}, fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		return counts{}, fmt.Errorf("%s: Is a directory", file)
+	}
+	c, err := count(f)
+	if err != nil {
+		return c, fmt.Errorf("%s: %v", file, cli.Unwrap(err))
+	}
+	return c, nil
+}
+
+// count reads r to the end and tallies it. Words are maximal runs of
+// bytes other than ASCII whitespace, and characters are UTF-8 sequences,
+// counted by their leading bytes so that a character split between two
+// reads is still counted once.
+func count(r io.Reader) (counts, error) {
+	var c counts
+	inWord := false
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		c.bytes += int64(n)
+		for _, b := range buf[:n] {
+			if b == '\n' {
+				c.lines++
+			}
+			// Continuation bytes (10xxxxxx) never start a character.
+			if b&0xC0 != 0x80 {
+				c.chars++
+			}
+			if isSpace(b) {
+				inWord = false
+			} else if !inWord {
+				inWord = true
+				c.words++
+			}
+		}
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return c, err
+		}
+	}
+}
+
+// isSpace reports whether b is an ASCII whitespace byte.
+func isSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// columnWidth returns the width that right-aligns every selected count:
+// that of the largest one, which is always found in the totals.
+func columnWidth(total counts, opts *options) int {
+	var largest int64
+	if opts.lines {
+		largest = max(largest, total.lines)
+	}
+	if opts.words {
+		largest = max(largest, total.words)
+	}
+	if opts.chars {
+		largest = max(largest, total.chars)
+	}
+	if opts.bytes {
+		largest = max(largest, total.bytes)
+	}
+	return len(strconv.FormatInt(largest, 10))
+}
+
+// printCounts prints the selected counts of r, in the order lines, words,
+// characters, bytes, followed by its name if it has one.
+func printCounts(w io.Writer, r result, width int, opts *options) {
+	var fields []int64
+	if opts.lines {
+		fields = append(fields, r.counts.lines)
+	}
+	if opts.words {
+		fields = append(fields, r.counts.words)
+	}
+	if opts.chars {
+		fields = append(fields, r.counts.chars)
+	}
+	if opts.bytes {
+		fields = append(fields, r.counts.bytes)
+	}
+	for i, n := range fields {
+		if i > 0 {
+			fmt.Fprint(w, " ")
+		}
+		fmt.Fprintf(w, "%*d", width, n)
+	}
+	if r.name != "" {
+		fmt.Fprintf(w, " %s", r.name)
+	}
+	fmt.Fprintln(w)
+}
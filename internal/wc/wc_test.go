@@ -0,0 +1,70 @@
+package wc
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drunkleen/unix-tools-go/internal/testutil"
+)
+
+func TestFlags(t *testing.T) {
+	input := "héllo world\n  two  words \nlast"
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, " 2  5 31\n"},
+		{[]string{"-l"}, "2\n"},
+		{[]string{"-w"}, "5\n"},
+		{[]string{"-c"}, "31\n"},
+		{[]string{"-m"}, "30\n"},
+		{[]string{"-l", "-m"}, " 2 30\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, strings.NewReader(input), &stdout, &stderr)
+		if stdout.String() != tt.expected || status != 0 {
+			t.Errorf("wc %v: Expected %q (0) but got %q (%d)", tt.args, tt.expected, stdout.String(), status)
+		}
+	}
+}
+
+func TestEmptyInput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	run(nil, strings.NewReader(""), &stdout, &stderr)
+	if expected := "0 0 0\n"; stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+}
+
+func TestMultipleFiles(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "one two\n")
+	b := testutil.TempFile(t, "b.txt", strings.Repeat("x\n", 100))
+	var stdout, stderr bytes.Buffer
+	status := run([]string{a, b}, nil, &stdout, &stderr)
+	expected := "  1   2   8 " + a + "\n" +
+		"100 100 200 " + b + "\n" +
+		"101 102 208 total\n"
+	if stdout.String() != expected || status != 0 {
+		t.Errorf("Expected %q (0) but got %q (%d)", expected, stdout.String(), status)
+	}
+}
+
+func TestMissingFile(t *testing.T) {
+	a := testutil.TempFile(t, "a.txt", "x\n")
+	missing := filepath.Join(t.TempDir(), "missing")
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-l", a, missing}, nil, &stdout, &stderr)
+	if status != 1 {
+		t.Errorf("Expected status 1 but got %d", status)
+	}
+	expected := "1 " + a + "\n1 total\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout.String())
+	}
+	if expected := "wc: " + missing + ": no such file or directory\n"; stderr.String() != expected {
+		t.Errorf("Expected %q but got %q", expected, stderr.String())
+	}
+}
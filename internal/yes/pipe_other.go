@@ -0,0 +1,12 @@
+//go:build !unix
+
+package yes
+
+// ignoreBrokenPipe does nothing on platforms without SIGPIPE.
+func ignoreBrokenPipe() {}
+
+// isBrokenPipe reports that no write error is known to mean a broken pipe
+// on this platform.
+func isBrokenPipe(err error) bool {
+	return false
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package yes
+
+import (
+	"errors"    // For matching wrapped errors.
+	"os/signal" // For ignoring SIGPIPE.
+	"syscall"   // For the SIGPIPE signal and EPIPE error.
+)
+
+// ignoreBrokenPipe stops SIGPIPE from killing the process when the reader
+// of stdout goes away, so the write fails with EPIPE instead.
+func ignoreBrokenPipe() {
+	signal.Ignore(syscall.SIGPIPE)
+}
+
+// isBrokenPipe reports whether err says the reader of the output went away.
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
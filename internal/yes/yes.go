@@ -0,0 +1,48 @@
+// Package yes implements the functionality for the "yes" Unix tool.
+package yes
+
+import (
+	"flag"    // Used to parse command-line flags.
+	"io"      // Provides the writer abstraction used for output.
+	"os"      // For the standard streams.
+	"strings" // For joining the operands and filling the buffer.
+
+	"github.com/drunkleen/unix-tools-go/internal/cli" // Shared conventions for reporting errors.
+)
+
+// bufferSize is the approximate size of each write: the line is repeated
+// to fill it, so that output takes few system calls.
+const bufferSize = 64 * 1024
+
+// Run is the entry point for the yes functionality.
+// It prints its operands (or "y") joined by spaces, one line after
+// another, until output fails. It returns the exit status: 0 when the
+// reader went away (a broken pipe), or 1 on any other write error.
+func Run(args []string) int {
+	ignoreBrokenPipe()
+	return run(args, os.Stdout, os.Stderr)
+}
+
+// run performs the actual work of Run using the provided streams.
+func run(args []string, stdout, stderr io.Writer) int {
+	// Create a new FlagSet for parsing command-line options specific to "yes".
+	fs := flag.NewFlagSet("yes", flag.ExitOnError)
+	fs.SetOutput(stderr)
+	fs.Parse(args)
+
+	line := "y\n"
+	if fs.NArg() > 0 {
+		line = strings.Join(fs.Args(), " ") + "\n"
+	}
+	// Repeat whole lines only, so every write ends at a line break.
+	buf := []byte(strings.Repeat(line, max(1, bufferSize/len(line))))
+	for {
+		if _, err := stdout.Write(buf); err != nil {
+			if isBrokenPipe(err) {
+				return cli.ExitSuccess
+			}
+			cli.Fprintf(stderr, "yes", "write error: %v", err)
+			return cli.ExitFailure
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package yes
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// limitWriter accepts up to limit bytes and then fails with err.
+type limitWriter struct {
+	buf   bytes.Buffer
+	limit int
+	err   error
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.limit {
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
+
+func TestRepeats(t *testing.T) {
+	tests := []struct {
+		args []string
+		line string
+	}{
+		{nil, "y"},
+		{[]string{"hello", "there"}, "hello there"},
+	}
+	for _, tt := range tests {
+		w := &limitWriter{limit: 1 << 20, err: errors.New("disk full")}
+		var stderr bytes.Buffer
+		status := run(tt.args, w, &stderr)
+		if status != 1 || stderr.String() != "yes: write error: disk full\n" {
+			t.Errorf("yes %v: Expected a write error (1) but got %q (%d)", tt.args, stderr.String(), status)
+		}
+		lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\n"), "\n")
+		if len(lines) < 1000 {
+			t.Fatalf("yes %v: Expected at least 1000 lines but got %d", tt.args, len(lines))
+		}
+		for i, line := range lines[:1000] {
+			if line != tt.line {
+				t.Fatalf("yes %v: Expected %q on line %d but got %q", tt.args, tt.line, i+1, line)
+			}
+		}
+	}
+}
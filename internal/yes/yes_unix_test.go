@@ -0,0 +1,30 @@
+//go:build unix
+
+package yes
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"syscall"
+	"testing"
+)
+
+func TestBrokenPipe(t *testing.T) {
+	pr, pw := io.Pipe()
+	done := make(chan int)
+	var stderr bytes.Buffer
+	go func() { done <- run([]string{"ok"}, pw, &stderr) }()
+
+	// Read a bounded number of lines, then hang up like "yes | head" would.
+	scanner := bufio.NewScanner(pr)
+	for i := 0; i < 100; i++ {
+		if !scanner.Scan() || scanner.Text() != "ok" {
+			t.Fatalf("Expected %q on line %d but got %q", "ok", i+1, scanner.Text())
+		}
+	}
+	pr.CloseWithError(syscall.EPIPE)
+	if status := <-done; status != 0 || stderr.Len() != 0 {
+		t.Errorf("Expected a clean exit (0) but got %q (%d)", stderr.String(), status)
+	}
+}